@@ -0,0 +1,77 @@
+package templ
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+type clockKeyType int
+
+const clockKey = clockKeyType(0)
+
+type randKeyType int
+
+const randKey = randKeyType(0)
+
+// WithClock sets the function used by Now to get the current time.
+// It's typically used in tests, via templtest.WithFrozenTime, so that rendered
+// output doesn't change between runs.
+func WithClock(ctx context.Context, now func() time.Time) context.Context {
+	return context.WithValue(ctx, clockKey, now)
+}
+
+// Now returns the current time using the clock set with WithClock, or
+// time.Now if no clock has been set.
+//
+// Components that render the current time should use Now rather than
+// time.Now, so that they can be rendered deterministically in tests.
+func Now(ctx context.Context) time.Time {
+	if ctx != nil {
+		if now, ok := ctx.Value(clockKey).(func() time.Time); ok && now != nil {
+			return now()
+		}
+	}
+	return time.Now()
+}
+
+// WithRandSource sets the source of random numbers returned by Rand. The
+// source is shared by all components rendered with the returned context, and
+// is safe for concurrent use.
+func WithRandSource(ctx context.Context, src rand.Source) context.Context {
+	return context.WithValue(ctx, randKey, rand.New(&lockedSource{src: src}))
+}
+
+// Rand returns the random number generator set with WithRandSource, or a
+// generator that uses the default source if none has been set.
+//
+// Components that render random values, such as element IDs, should use Rand
+// so that they can be rendered deterministically in tests.
+func Rand(ctx context.Context) *rand.Rand {
+	if ctx != nil {
+		if r, ok := ctx.Value(randKey).(*rand.Rand); ok {
+			return r
+		}
+	}
+	return defaultRand
+}
+
+var defaultRand = rand.New(globalSource{})
+
+// globalSource uses the top-level functions of math/rand/v2, which are safe
+// for concurrent use.
+type globalSource struct{}
+
+func (globalSource) Uint64() uint64 { return rand.Uint64() }
+
+type lockedSource struct {
+	m   sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.src.Uint64()
+}
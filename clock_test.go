@@ -0,0 +1,63 @@
+package templ_test
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+func TestNow(t *testing.T) {
+	t.Run("without a clock, the current time is returned", func(t *testing.T) {
+		before := time.Now()
+		actual := templ.Now(context.Background())
+		if actual.Before(before) || actual.After(time.Now()) {
+			t.Errorf("expected the current time, got %v", actual)
+		}
+	})
+	t.Run("the clock set on the context is used", func(t *testing.T) {
+		expected := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+		ctx := templ.WithClock(context.Background(), func() time.Time { return expected })
+		if actual := templ.Now(ctx); !actual.Equal(expected) {
+			t.Errorf("expected %v, got %v", expected, actual)
+		}
+	})
+}
+
+func TestRand(t *testing.T) {
+	t.Run("without a source, a random generator is returned", func(t *testing.T) {
+		if templ.Rand(context.Background()) == nil {
+			t.Fatal("expected a generator, got nil")
+		}
+	})
+	t.Run("the same seed produces the same values", func(t *testing.T) {
+		values := func() (v []uint64) {
+			ctx := templ.WithRandSource(context.Background(), rand.NewPCG(1, 2))
+			for range 3 {
+				v = append(v, templ.Rand(ctx).Uint64())
+			}
+			return v
+		}
+		a, b := values(), values()
+		for i := range a {
+			if a[i] != b[i] {
+				t.Fatalf("expected the same values, got %v and %v", a, b)
+			}
+		}
+	})
+	t.Run("the source is safe for concurrent use", func(t *testing.T) {
+		ctx := templ.WithRandSource(context.Background(), rand.NewPCG(1, 2))
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Go(func() {
+				for range 100 {
+					templ.Rand(ctx).IntN(100)
+				}
+			})
+		}
+		wg.Wait()
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -47,6 +48,31 @@ type Generate struct {
 	ShouldSkip func(string) bool
 }
 
+// init resolves the path to generate, and sets up the rules for skipping files.
+func (cmd *Generate) init() (err error) {
+	// Use absolute path.
+	if !path.IsAbs(cmd.Args.Path) {
+		cmd.Args.Path, err = filepath.Abs(cmd.Args.Path)
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path: %w", err)
+		}
+	}
+	// Resolve symlinks, so that walked files, watched files, and the dev mode
+	// watch root all use the same paths.
+	if resolved, err := filepath.EvalSymlinks(cmd.Args.Path); err == nil {
+		cmd.Args.Path = resolved
+	}
+
+	// Load ignore patterns.
+	isIgnored, err := ignorefile.ShouldSkipFunc(cmd.Args.Path, ".templignore_generate")
+	if err != nil {
+		return fmt.Errorf("failed to parse .templignore_generate: %w", err)
+	}
+	cmd.ShouldSkip = shouldSkipFunc(cmd.Args.Path, isIgnored, cmd.Args.Include, cmd.Args.Exclude)
+
+	return nil
+}
+
 type GenerationEvent struct {
 	Event                fsnotify.Event
 	GoFileWritten        bool
@@ -65,18 +91,8 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 		}()
 	}
 
-	// Use absolute path.
-	if !path.IsAbs(cmd.Args.Path) {
-		cmd.Args.Path, err = filepath.Abs(cmd.Args.Path)
-		if err != nil {
-			return fmt.Errorf("failed to get absolute path: %w", err)
-		}
-	}
-
-	// Load ignore patterns.
-	cmd.ShouldSkip, err = ignorefile.ShouldSkipFunc(cmd.Args.Path, ".templignore_generate")
-	if err != nil {
-		return fmt.Errorf("failed to parse .templignore_generate: %w", err)
+	if err = cmd.init(); err != nil {
+		return err
 	}
 
 	// Configure generator.
@@ -87,6 +103,9 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 	if cmd.Args.IncludeTimestamp {
 		opts = append(opts, generator.WithTimestamp(time.Now()))
 	}
+	if cmd.Args.StrictCSP {
+		opts = append(opts, generator.WithStrictCSP())
+	}
 
 	// Check the version of the templ module.
 	if err := modcheck.Check(cmd.Args.Path); err != nil {
@@ -149,7 +168,7 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 	})
 
 	// Read errors.
-	var errorCount int
+	var generationErrors []error
 	for err := range errs {
 		if err == nil {
 			continue
@@ -159,7 +178,7 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 			return err
 		}
 		cmd.Log.Error("Error", slog.Any("error", err))
-		errorCount++
+		generationErrors = append(generationErrors, err)
 	}
 
 	// Wait for everything to complete.
@@ -180,15 +199,17 @@ func (cmd Generate) Run(ctx context.Context) (err error) {
 	}
 
 	// Check for errors after everything has completed.
-	if errorCount > 0 {
-		return fmt.Errorf("generation completed with %d errors", errorCount)
+	if len(generationErrors) > 0 {
+		return GenerationErrors{Errors: generationErrors}
 	}
 
 	cmd.Log.Info("Complete", slog.Int("updates", updates), slog.Duration("duration", time.Since(start)))
 	return nil
 }
 
-func (cmd Generate) groupUntilNoMessagesReceivedFor100ms(postGeneration chan *GenerationEvent) (grouped *GenerationEvent, updates int, ok bool, err error) {
+// groupUntilNoMessagesReceivedFor100ms combines the events received until there's a
+// 100ms pause, and returns the names of the files that changed, relative to the path.
+func (cmd Generate) groupUntilNoMessagesReceivedFor100ms(postGeneration chan *GenerationEvent) (grouped *GenerationEvent, files []string, updates int, ok bool, err error) {
 	timeout := time.NewTimer(time.Hour * 24 * 365)
 loop:
 	for {
@@ -196,10 +217,10 @@ loop:
 		case ge := <-postGeneration:
 			if ge == nil {
 				cmd.Log.Debug("Post-generation event channel closed, exiting")
-				if grouped != nil {
-					return grouped, updates, true, nil
+				if grouped == nil {
+					return nil, nil, 0, false, nil
 				}
-				return nil, 0, false, nil
+				break loop
 			}
 			if grouped == nil {
 				grouped = ge
@@ -211,6 +232,9 @@ loop:
 			if ge.GoFileWritten {
 				updates++
 			}
+			if name := cmd.relativeFileName(ge.Event.Name); !slices.Contains(files, name) {
+				files = append(files, name)
+			}
 			// Now we have received an event, wait for 100ms.
 			// If no further messages are received in that time, the timeout will trigger.
 			timeout = time.NewTimer(time.Millisecond * 100)
@@ -221,9 +245,19 @@ loop:
 				continue loop
 			}
 			// We have a grouped event, and no events have been sent in the last 100ms, so we need to return.
-			return grouped, updates, true, nil
+			break loop
 		}
 	}
+	slices.Sort(files)
+	return grouped, files, updates, true, nil
+}
+
+// relativeFileName returns the name of the file relative to the path, using forward slashes.
+func (cmd Generate) relativeFileName(fileName string) string {
+	if rel, err := filepath.Rel(cmd.Args.Path, fileName); err == nil {
+		fileName = rel
+	}
+	return filepath.ToSlash(fileName)
 }
 
 func (cmd Generate) handlePostGenerationEvents(ctx context.Context, postGeneration chan *GenerationEvent) (updates int, err error) {
@@ -231,7 +265,7 @@ func (cmd Generate) handlePostGenerationEvents(ctx context.Context, postGenerati
 	var p *proxy.Handler
 loop:
 	for {
-		grouped, updated, ok, err := cmd.groupUntilNoMessagesReceivedFor100ms(postGeneration)
+		grouped, files, updated, ok, err := cmd.groupUntilNoMessagesReceivedFor100ms(postGeneration)
 		if err != nil {
 			return 0, fmt.Errorf("error grouping post-generation events: %w", err)
 		}
@@ -259,12 +293,8 @@ loop:
 				if !filepath.IsAbs(cmd.Args.Path) {
 					cmd.Log.Error("Path is not absolute, this may cause issues with the command execution", slog.String("path", cmd.Args.Path))
 				}
-				// Evaluate symlinks to match the behavior in runtime/watchmode.go.
-				watchRoot := cmd.Args.Path
-				if resolved, err := filepath.EvalSymlinks(watchRoot); err == nil {
-					watchRoot = resolved
-				}
-				if err := os.Setenv("TEMPL_DEV_MODE_WATCH_ROOT", watchRoot); err != nil {
+				// The path has already had symlinks evaluated, to match the behavior in runtime/watchmode.go.
+				if err := os.Setenv("TEMPL_DEV_MODE_WATCH_ROOT", cmd.Args.Path); err != nil {
 					cmd.Log.Error("Error setting TEMPL_DEV_MODE_WATCH_ROOT environment variable", slog.Any("error", err))
 				}
 			}
@@ -281,8 +311,10 @@ loop:
 				}
 			}
 			if needsBrowserReload {
-				cmd.Log.Debug("Sending reload event")
-				p.SendSSE("message", "reload")
+				cmd.Log.Debug("Sending reload event", slog.Any("files", files))
+				if err := p.SendReload(files); err != nil {
+					cmd.Log.Error("Failed to send reload event", slog.Any("error", err))
+				}
 			}
 		}
 	}
@@ -355,6 +387,31 @@ func (cmd *Generate) walkAndWatch(ctx context.Context, events chan fsnotify.Even
 	<-ctx.Done()
 }
 
+// shouldSkipFunc combines the ignore file with the -include and -exclude patterns.
+// Include patterns only apply to templ files, so that directories are still walked,
+// and changes to other watched files are still picked up.
+func shouldSkipFunc(root string, isIgnored func(string) bool, include, exclude ignorefile.Patterns) func(string) bool {
+	return func(path string) bool {
+		if isIgnored(path) {
+			return true
+		}
+		if filepath.IsAbs(path) {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return false
+			}
+			path = rel
+		}
+		if exclude.Matches(path) {
+			return true
+		}
+		if len(include) > 0 && strings.HasSuffix(path, ".templ") {
+			return !include.Matches(path)
+		}
+		return false
+	}
+}
+
 func (cmd *Generate) deleteWatchModeTextFiles() error {
 	return fs.WalkDir(os.DirFS(cmd.Args.Path), ".", func(path string, info os.DirEntry, err error) error {
 		if err != nil {
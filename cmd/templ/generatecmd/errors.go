@@ -0,0 +1,121 @@
+package generatecmd
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/scanner"
+	"io"
+	"slices"
+
+	"github.com/a-h/parse"
+	"github.com/a-h/templ/generator"
+)
+
+// Error codes used in machine-readable error output.
+const (
+	ErrorCodeParse     = "parse"
+	ErrorCodeStrictCSP = "strict-csp"
+	ErrorCodeFormat    = "format"
+	ErrorCodeTypeCheck = "typecheck"
+	ErrorCodeGenerate  = "generate"
+)
+
+// FileError is a single error, with its position in a templ file, in the
+// format written by -errformat=json.
+type FileError struct {
+	FileName string `json:"file"`
+	// Line and Col are 1-based, and are zero if the position is unknown.
+	Line    int    `json:"line"`
+	Col     int    `json:"col"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// GenerationError is returned when code can't be generated for a templ file.
+type GenerationError struct {
+	FileName string
+	Err      error
+}
+
+func (e GenerationError) Error() string {
+	return fmt.Sprintf("failed to generate code for %q: %v", e.FileName, e.Err)
+}
+
+func (e GenerationError) Unwrap() error {
+	return e.Err
+}
+
+// GenerationErrors is returned by Run when one or more files failed to generate.
+type GenerationErrors struct {
+	Errors []error
+}
+
+func (e GenerationErrors) Error() string {
+	return fmt.Sprintf("generation completed with %d errors", len(e.Errors))
+}
+
+// FileErrors returns each of the errors, expanded so that every problem
+// found in a file is a separate entry.
+func (e GenerationErrors) FileErrors() (fileErrors []FileError) {
+	for _, err := range e.Errors {
+		var ge GenerationError
+		if !errors.As(err, &ge) {
+			fileErrors = append(fileErrors, FileError{Code: ErrorCodeGenerate, Message: err.Error()})
+			continue
+		}
+		fileErrors = append(fileErrors, expandFileErrors(ge.FileName, ge.Err)...)
+	}
+	// Files are generated concurrently, so sort the errors to make the output stable.
+	slices.SortStableFunc(fileErrors, func(a, b FileError) int {
+		return cmp.Or(
+			cmp.Compare(a.FileName, b.FileName),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Col, b.Col),
+		)
+	})
+	return fileErrors
+}
+
+func expandFileErrors(fileName string, err error) (fileErrors []FileError) {
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		for _, err := range joined.Unwrap() {
+			fileErrors = append(fileErrors, expandFileErrors(fileName, err)...)
+		}
+		return fileErrors
+	}
+	var list scanner.ErrorList
+	if errors.As(err, &list) {
+		for _, e := range list {
+			fe := FileError{FileName: fileName, Code: ErrorCodeFormat, Message: e.Msg}
+			// Positions that couldn't be mapped back to the templ file refer to the generated code.
+			if e.Pos.Filename != "" {
+				fe.Line, fe.Col = e.Pos.Line, e.Pos.Column
+			}
+			fileErrors = append(fileErrors, fe)
+		}
+		return fileErrors
+	}
+	var cspErr generator.StrictCSPError
+	if errors.As(err, &cspErr) {
+		return []FileError{{FileName: fileName, Line: int(cspErr.Pos.Line) + 1, Col: int(cspErr.Pos.Col) + 1, Code: ErrorCodeStrictCSP, Message: cspErr.Msg}}
+	}
+	var pe parse.ParseError
+	if errors.As(err, &pe) {
+		return []FileError{{FileName: fileName, Line: pe.Pos.Line + 1, Col: pe.Pos.Col + 1, Code: ErrorCodeParse, Message: pe.Msg}}
+	}
+	return []FileError{{FileName: fileName, Code: ErrorCodeGenerate, Message: err.Error()}}
+}
+
+// writeFileErrors writes the errors to w as a JSON array.
+func writeFileErrors(w io.Writer, fileErrors []FileError) error {
+	if fileErrors == nil {
+		fileErrors = []FileError{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(fileErrors)
+}
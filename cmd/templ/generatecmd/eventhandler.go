@@ -179,7 +179,7 @@ func (h *FSEventHandler) HandleEvent(ctx context.Context, event fsnotify.Event)
 	result, diag, err = h.generate(ctx, event.Name)
 	if err != nil {
 		h.fileNameToError.Set(event.Name)
-		return result, fmt.Errorf("failed to generate code for %q: %w", event.Name, err)
+		return result, GenerationError{FileName: event.Name, Err: err}
 	}
 	if len(diag) > 0 {
 		for _, d := range diag {
@@ -221,6 +221,11 @@ func (h *FSEventHandler) generate(ctx context.Context, fileName string) (result
 	if err != nil {
 		return GenerateResult{}, nil, fmt.Errorf("failed to get absolute path for %q: %w", fileName, err)
 	}
+	// The base path has had symlinks resolved, so resolve the file's directory
+	// too, otherwise a symlinked working directory appears in the relative path.
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(absFilePath)); err == nil {
+		absFilePath = filepath.Join(dir, filepath.Base(absFilePath))
+	}
 	relFilePath, err := filepath.Rel(h.dir, absFilePath)
 	if err != nil {
 		return GenerateResult{}, nil, fmt.Errorf("failed to get relative path for %q: %w", fileName, err)
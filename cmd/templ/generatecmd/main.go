@@ -3,16 +3,19 @@ package generatecmd
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log/slog"
+	"path/filepath"
 	"regexp"
 	"runtime"
 
 	_ "net/http/pprof"
 
 	"github.com/a-h/templ/cmd/templ/sloghandler"
+	"github.com/a-h/templ/internal/ignorefile"
 )
 
 const generateUsageText = `usage: templ generate [<args>...]
@@ -39,6 +42,12 @@ Args:
     Set the regexp pattern of files that will be watched for changes. (default: '(.+\.go$)|(.+\.templ$)|(.+_templ\.txt$)')
   -ignore-pattern <regexp>
     Set the regexp pattern of files to ignore when watching for changes. (default: '')
+  -include <glob>
+    Only generate code for templ files that match the glob pattern. Can be repeated.
+    Patterns are matched against the path relative to -path, and each of its parent directories.
+  -exclude <glob>
+    Skip files and directories that match the glob pattern, in addition to those listed
+    in .templignore_generate. Can be repeated.
   -cmd <cmd>
     Set the command to run after generating code. The command is executed via
     the system shell ($SHELL on Unix, %COMSPEC% on Windows).
@@ -61,6 +70,22 @@ Args:
   -check
     Checks that generated files are up to date, without writing changes.
     Returns a non-zero exit code if any files need regenerating.
+  -strict-csp
+    Fails generation if templates contain inline event handler attributes, such as
+    onclick, or javascript: URLs, which require a Content-Security-Policy that
+    allows 'unsafe-inline'. Only constant URLs, and templ.SafeURL conversions of
+    string constants, are checked. (default false)
+  -typecheck
+    Type checks the Go expressions in templ files, without writing changes.
+    Errors are reported with their position in the templ file, and a non-zero
+    exit code is returned if any are found.
+  -tags <tags>
+    Comma-separated list of build tags to use when type checking with -typecheck.
+    GOFLAGS, GOOS and GOARCH are also read from the environment.
+  -errformat <format>
+    Set the format of the error report. With "json", every error found is written to
+    stdout as a JSON array of {file, line, col, code, message} objects when generation
+    completes. Cannot be used with -watch or -stdout. (default "text", options: "text", "json")
   -v
     Set log verbosity level to "debug". (default "info")
   -log-level
@@ -85,6 +110,14 @@ Examples:
   Check generated code is up to date (e.g. in CI):
 
     templ generate -check
+
+  Type check templ files without generating code (e.g. in CI):
+
+    templ generate -typecheck
+
+  Report all errors as JSON, for use by editors and CI:
+
+    templ generate -errformat=json
 `
 
 const defaultWatchPattern = `(.+\.go$)|(.+\.templ$)`
@@ -100,6 +133,20 @@ func NewArguments(stdout, stderr io.Writer, args []string) (cmdArgs Arguments, l
 	cmd.BoolVar(&cmdArgs.Watch, "watch", false, "")
 	watchPatternFlag := cmd.String("watch-pattern", defaultWatchPattern, "")
 	ignorePatternFlag := cmd.String("ignore-pattern", "", "")
+	cmd.Func("include", "", func(pattern string) error {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+		cmdArgs.Include = append(cmdArgs.Include, pattern)
+		return nil
+	})
+	cmd.Func("exclude", "", func(pattern string) error {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+		cmdArgs.Exclude = append(cmdArgs.Exclude, pattern)
+		return nil
+	})
 	cmd.BoolVar(&cmdArgs.OpenBrowser, "open-browser", true, "")
 	cmd.StringVar(&cmdArgs.Command, "cmd", "", "")
 	cmd.StringVar(&cmdArgs.Proxy, "proxy", "", "")
@@ -113,6 +160,10 @@ func NewArguments(stdout, stderr io.Writer, args []string) (cmdArgs Arguments, l
 	cmd.BoolVar(&cmdArgs.KeepOrphanedFiles, "keep-orphaned-files", false, "")
 	cmd.BoolVar(&cmdArgs.Lazy, "lazy", false, "")
 	cmd.BoolVar(&cmdArgs.Check, "check", false, "")
+	cmd.BoolVar(&cmdArgs.TypeCheck, "typecheck", false, "")
+	cmd.BoolVar(&cmdArgs.StrictCSP, "strict-csp", false, "")
+	cmd.StringVar(&cmdArgs.Tags, "tags", "", "")
+	cmd.StringVar(&cmdArgs.ErrorFormat, "errformat", ErrorFormatText, "")
 	verboseFlag := cmd.Bool("v", false, "")
 	logLevelFlag := cmd.String("log-level", "info", "")
 	helpFlag := cmd.Bool("help", false, "")
@@ -131,6 +182,18 @@ func NewArguments(stdout, stderr io.Writer, args []string) (cmdArgs Arguments, l
 	if cmdArgs.Check && *toStdoutFlag {
 		return Arguments{}, log, *helpFlag, fmt.Errorf("cannot use -check with -stdout")
 	}
+	if cmdArgs.TypeCheck && (cmdArgs.Watch || cmdArgs.Check || *toStdoutFlag || cmdArgs.FileName != "") {
+		return Arguments{}, log, *helpFlag, fmt.Errorf("cannot use -typecheck with -watch, -check, -stdout or -f")
+	}
+	if cmdArgs.Tags != "" && !cmdArgs.TypeCheck {
+		return Arguments{}, log, *helpFlag, fmt.Errorf("-tags can only be used with -typecheck")
+	}
+	if cmdArgs.ErrorFormat != ErrorFormatText && cmdArgs.ErrorFormat != ErrorFormatJSON {
+		return Arguments{}, log, *helpFlag, fmt.Errorf("invalid -errformat %q, expected %q or %q", cmdArgs.ErrorFormat, ErrorFormatText, ErrorFormatJSON)
+	}
+	if cmdArgs.ErrorFormat == ErrorFormatJSON && (cmdArgs.Watch || *toStdoutFlag) {
+		return Arguments{}, log, *helpFlag, fmt.Errorf("cannot use -errformat=json with -watch or -stdout")
+	}
 	cmdArgs.WatchPattern, err = regexp.Compile(*watchPatternFlag)
 	if err != nil {
 		return cmdArgs, log, *helpFlag, fmt.Errorf("invalid watch pattern %q: %w", *watchPatternFlag, err)
@@ -186,8 +249,25 @@ type Arguments struct {
 	PPROFPort         int
 	KeepOrphanedFiles bool
 	Lazy              bool
+	// Include limits generation to templ files that match the patterns.
+	Include ignorefile.Patterns
+	// Exclude skips files and directories that match the patterns.
+	Exclude ignorefile.Patterns
+	// TypeCheck type checks the generated code without writing it.
+	TypeCheck bool
+	// StrictCSP fails generation if templates use inline event handlers or javascript: URLs.
+	StrictCSP bool
+	// Tags is a comma-separated list of build tags used when type checking.
+	Tags string
+	// ErrorFormat is the format of the error report, ErrorFormatText or ErrorFormatJSON.
+	ErrorFormat string
 }
 
+const (
+	ErrorFormatText = "text"
+	ErrorFormatJSON = "json"
+)
+
 type ArgumentError struct {
 	Message string
 }
@@ -212,6 +292,34 @@ func Run(ctx context.Context, stdout, stderr io.Writer, args []string) (err erro
 		_, _ = fmt.Fprint(stdout, generateUsageText)
 		return nil
 	}
+	if cmdArgs.TypeCheck {
+		g, err := NewGenerate(log, cmdArgs)
+		if err != nil {
+			return err
+		}
+		typeErrors, err := g.TypeCheck(ctx)
+		if err != nil {
+			return err
+		}
+		if cmdArgs.ErrorFormat == ErrorFormatJSON {
+			fileErrors := make([]FileError, len(typeErrors))
+			for i, te := range typeErrors {
+				fileErrors[i] = FileError{FileName: te.FileName, Line: te.Line, Col: te.Col, Code: ErrorCodeTypeCheck, Message: te.Message}
+			}
+			if err := writeFileErrors(stdout, fileErrors); err != nil {
+				return err
+			}
+		} else {
+			for _, te := range typeErrors {
+				_, _ = fmt.Fprintln(stdout, te.String())
+			}
+		}
+		if len(typeErrors) > 0 {
+			return fmt.Errorf("type check failed: %d error(s)", len(typeErrors))
+		}
+		log.Info("Type check complete")
+		return nil
+	}
 	if cmdArgs.Check {
 		var getChanged func() []string
 		cmdArgs.FileWriter, getChanged = NewCheckWriter()
@@ -219,7 +327,7 @@ func Run(ctx context.Context, stdout, stderr io.Writer, args []string) (err erro
 		if err != nil {
 			return err
 		}
-		if err := g.Run(ctx); err != nil {
+		if err := writeErrors(stdout, cmdArgs.ErrorFormat, g.Run(ctx)); err != nil {
 			return err
 		}
 		if changed := getChanged(); len(changed) > 0 {
@@ -234,5 +342,22 @@ func Run(ctx context.Context, stdout, stderr io.Writer, args []string) (err erro
 	if err != nil {
 		return err
 	}
-	return g.Run(ctx)
+	return writeErrors(stdout, cmdArgs.ErrorFormat, g.Run(ctx))
+}
+
+// writeErrors writes the errors from a generation run to stdout if the JSON
+// error format is selected, and returns err.
+func writeErrors(stdout io.Writer, errorFormat string, err error) error {
+	if errorFormat != ErrorFormatJSON {
+		return err
+	}
+	var ge GenerationErrors
+	if err != nil && !errors.As(err, &ge) {
+		// Errors that stop generation before any files are processed aren't collected.
+		return err
+	}
+	if writeErr := writeFileErrors(stdout, ge.FileErrors()); writeErr != nil {
+		return writeErr
+	}
+	return err
 }
@@ -3,7 +3,9 @@ package generatecmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"io"
+	"log/slog"
 	"os"
 	"path"
 	"regexp"
@@ -12,7 +14,10 @@ import (
 	"time"
 
 	"github.com/a-h/templ/cmd/templ/testproject"
+	"github.com/a-h/templ/internal/ignorefile"
 	"github.com/a-h/templ/runtime"
+	"github.com/fsnotify/fsnotify"
+	"github.com/google/go-cmp/cmp"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -105,6 +110,48 @@ func TestGenerate(t *testing.T) {
 			t.Fatalf("expected 'not up to date' error, got: %v", err)
 		}
 	})
+	t.Run("errformat=json reports every error as JSON", func(t *testing.T) {
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Logf("failed to remove temp dir: %v", err)
+			}
+		}()
+
+		files := map[string]string{
+			"csp.templ":   "package main\n\ntempl csp() {\n\t<button onclick=\"go()\">Go</button>\n\t<a href=\"javascript:go()\">Go</a>\n}\n",
+			"parse.templ": "package main\n\ntempl parse() {\n\t<div>\n}\n",
+		}
+		for name, contents := range files {
+			if err := os.WriteFile(path.Join(dir, name), []byte(contents), 0o644); err != nil {
+				t.Fatalf("failed to write %s: %v", name, err)
+			}
+		}
+
+		stdout := &bytes.Buffer{}
+		err = Run(context.Background(), stdout, io.Discard, []string{"-path", dir, "-strict-csp", "-errformat", "json"})
+		if err == nil {
+			t.Fatal("expected generation to fail")
+		}
+		var actual []FileError
+		if err := json.Unmarshal(stdout.Bytes(), &actual); err != nil {
+			t.Fatalf("failed to unmarshal output %q: %v", stdout.String(), err)
+		}
+		for i := range actual {
+			actual[i].FileName = path.Base(actual[i].FileName)
+		}
+		expected := []FileError{
+			{FileName: "csp.templ", Line: 4, Col: 10, Code: ErrorCodeStrictCSP, Message: `inline event handler attribute "onclick" is not allowed, use a script with a nonce to add event listeners`},
+			{FileName: "csp.templ", Line: 5, Col: 11, Code: ErrorCodeStrictCSP, Message: `javascript: URL in "href" attribute is not allowed`},
+			{FileName: "parse.templ", Line: 5, Col: 1, Code: ErrorCodeParse, Message: "<div>: close tag not found"},
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Error(diff)
+		}
+	})
 	t.Run("can generate a file in watch mode", func(t *testing.T) {
 		// templ generate -f templates.templ
 		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
@@ -387,4 +434,113 @@ func TestArgs(t *testing.T) {
 			t.Fatal("expected error when -check and -stdout are both set")
 		}
 	})
+	t.Run("-tags sets Tags when used with -typecheck", func(t *testing.T) {
+		args, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-typecheck", "-tags", "integration,linux"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if args.Tags != "integration,linux" {
+			t.Fatalf("expected tags to be 'integration,linux', got '%s'", args.Tags)
+		}
+	})
+	t.Run("-tags without -typecheck returns an error", func(t *testing.T) {
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-tags", "integration"})
+		if err == nil {
+			t.Fatal("expected error when -tags is set without -typecheck")
+		}
+	})
+	t.Run("-errformat defaults to text", func(t *testing.T) {
+		args, _, _, err := NewArguments(io.Discard, io.Discard, []string{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if args.ErrorFormat != ErrorFormatText {
+			t.Fatalf("expected error format to be %q, got %q", ErrorFormatText, args.ErrorFormat)
+		}
+	})
+	t.Run("-errformat must be text or json", func(t *testing.T) {
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-errformat", "xml"})
+		if err == nil {
+			t.Fatal("expected error when -errformat is invalid")
+		}
+	})
+	t.Run("-errformat=json with -watch returns an error", func(t *testing.T) {
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-errformat", "json", "-watch"})
+		if err == nil {
+			t.Fatal("expected error when -errformat=json and -watch are both set")
+		}
+	})
+	t.Run("-include and -exclude can be repeated", func(t *testing.T) {
+		args, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-include", "components", "-include", "pages", "-exclude", "testdata"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(ignorefile.Patterns{"components", "pages"}, args.Include); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff(ignorefile.Patterns{"testdata"}, args.Exclude); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("-include patterns are checked for validity", func(t *testing.T) {
+		_, _, _, err := NewArguments(io.Discard, io.Discard, []string{"-include", "invalid[pattern"})
+		if err == nil {
+			t.Fatal("expected error when include pattern is invalid")
+		}
+	})
+}
+
+func TestShouldSkipFunc(t *testing.T) {
+	isIgnored := func(path string) bool {
+		return path == "ignored.templ"
+	}
+	shouldSkip := shouldSkipFunc("/app", isIgnored, ignorefile.Patterns{"components"}, ignorefile.Patterns{"components/testdata", "_examples"})
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{path: "ignored.templ", expected: true},
+		{path: "components", expected: false},
+		{path: "components/button.templ", expected: false},
+		{path: "/app/components/button.templ", expected: false},
+		{path: "components/testdata", expected: true},
+		{path: "components/testdata/button.templ", expected: true},
+		{path: "_examples/button.templ", expected: true},
+		{path: "pages/index.templ", expected: true},
+		{path: "pages", expected: false},
+		{path: "pages/index.go", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if actual := shouldSkip(tt.path); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGroupUntilNoMessagesReceivedFor100ms(t *testing.T) {
+	cmd := Generate{
+		Log:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Args: Arguments{Path: "/app"},
+	}
+	postGeneration := make(chan *GenerationEvent, 3)
+	postGeneration <- &GenerationEvent{Event: fsnotify.Event{Name: "/app/b.templ"}, GoFileWritten: true}
+	postGeneration <- &GenerationEvent{Event: fsnotify.Event{Name: "/app/a.templ"}, GoFileWritten: true}
+	postGeneration <- &GenerationEvent{Event: fsnotify.Event{Name: "/app/b.templ"}, GoFileWritten: true}
+	close(postGeneration)
+
+	_, files, updates, ok, err := cmd.groupUntilNoMessagesReceivedFor100ms(postGeneration)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected a grouped event")
+	}
+	if updates != 3 {
+		t.Errorf("expected 3 updates, got %d", updates)
+	}
+	if diff := cmp.Diff([]string{"a.templ", "b.templ"}, files); diff != "" {
+		t.Error(diff)
+	}
 }
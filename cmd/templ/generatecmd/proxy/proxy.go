@@ -3,6 +3,7 @@ package proxy
 import (
 	"bytes"
 	"compress/gzip"
+	"encoding/json"
 	"fmt"
 	"io"
 	stdlog "log"
@@ -320,6 +321,29 @@ func (p *Handler) SendSSE(eventType string, data string) {
 	p.sse.Send(eventType, data)
 }
 
+// ChangesEvent is the data of the "changes" event sent to clients before a
+// reload, listing the files that changed, relative to the generate path.
+type ChangesEvent struct {
+	Files []string `json:"files"`
+}
+
+// SendReload sends a "changes" event listing the changed files, followed by
+// a reload message.
+func (p *Handler) SendReload(files []string) error {
+	if files == nil {
+		files = []string{}
+	}
+	data, err := json.Marshal(ChangesEvent{Files: files})
+	if err != nil {
+		return fmt.Errorf("failed to marshal changes event: %w", err)
+	}
+	p.sse.SendAll(
+		sse.Event{Type: "changes", Data: string(data)},
+		sse.Event{Type: "message", Data: "reload"},
+	)
+	return nil
+}
+
 type roundTripper struct {
 	maxRetries      int
 	initialDelay    time.Duration
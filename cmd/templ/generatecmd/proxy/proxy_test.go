@@ -12,6 +12,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -688,6 +689,92 @@ func TestProxy(t *testing.T) {
 			t.Fatalf("timeout waiting for sse response")
 		}
 	})
+	t.Run("reload: changed files are sent before the reload sse event", func(t *testing.T) {
+		// Arrange 1: create a test proxy server.
+		dummyHandler := func(w http.ResponseWriter, r *http.Request) {}
+		dummyServer := httptest.NewServer(http.HandlerFunc(dummyHandler))
+		defer dummyServer.Close()
+
+		u, err := url.Parse(dummyServer.URL)
+		if err != nil {
+			t.Fatalf("unexpected error parsing URL: %v", err)
+		}
+		log := slog.New(slog.NewJSONHandler(io.Discard, nil))
+		handler := New(log, "http", "0.0.0.0", 0, u)
+		proxyServer := httptest.NewServer(handler)
+		defer proxyServer.Close()
+
+		// Arrange 2: start a goroutine to listen for sse events.
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		errChan := make(chan error, 1)
+		sseRespCh := make(chan []string)
+		sseListening := make(chan bool)
+		go func() {
+			req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/_templ/reload/events", proxyServer.URL), nil)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+
+			sseListening <- true
+			var lines, event []string
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				if scanner.Text() != "" {
+					event = append(event, scanner.Text())
+					continue
+				}
+				// Events are separated by blank lines, skip the pings.
+				if !slices.Contains(event, "data: ping") {
+					lines = append(lines, event...)
+				}
+				if slices.Contains(event, "data: reload") {
+					sseRespCh <- lines
+					return
+				}
+				event = nil
+			}
+			errChan <- scanner.Err()
+		}()
+
+		// Act: send the reload.
+		select {
+		case <-sseListening:
+			if err := handler.SendReload([]string{"components/card.templ", "main.go"}); err != nil {
+				t.Fatalf("unexpected error sending reload: %v", err)
+			}
+		case err := <-errChan:
+			t.Fatalf("unexpected sse error: %v", err)
+		}
+
+		// Assert.
+		select {
+		case lines := <-sseRespCh:
+			expected := []string{
+				"event: changes",
+				`data: {"files":["components/card.templ","main.go"]}`,
+				"event: message",
+				"data: reload",
+			}
+			if diff := cmp.Diff(expected, lines); diff != "" {
+				t.Error(diff)
+			}
+		case err := <-errChan:
+			t.Fatalf("unexpected sse error: %v", err)
+		case <-ctx.Done():
+			t.Fatalf("timeout waiting for sse response")
+		}
+	})
 	t.Run("unsupported encodings result in a warning", func(t *testing.T) {
 		// Arrange
 		r := &http.Response{
@@ -12,31 +12,35 @@ import (
 func New() *Handler {
 	return &Handler{
 		m:        new(sync.Mutex),
-		requests: map[int64]chan event{},
+		requests: map[int64]chan Event{},
 	}
 }
 
 type Handler struct {
 	m        *sync.Mutex
 	counter  int64
-	requests map[int64]chan event
+	requests map[int64]chan Event
 }
 
-type event struct {
+type Event struct {
 	Type string
 	Data string
 }
 
 // Send an event to all connected clients.
 func (s *Handler) Send(eventType string, data string) {
+	s.SendAll(Event{Type: eventType, Data: data})
+}
+
+// SendAll sends events to all connected clients. Each client receives the
+// events in order.
+func (s *Handler) SendAll(events ...Event) {
 	s.m.Lock()
 	defer s.m.Unlock()
 	for _, f := range s.requests {
-		f := f
-		go func(f chan event) {
-			f <- event{
-				Type: eventType,
-				Data: data,
+		go func(f chan Event) {
+			for _, e := range events {
+				f <- e
 			}
 		}(f)
 	}
@@ -51,7 +55,7 @@ func (s *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	id := atomic.AddInt64(&s.counter, 1)
 	s.m.Lock()
-	events := make(chan event)
+	events := make(chan Event)
 	s.requests[id] = events
 	s.m.Unlock()
 	defer func() {
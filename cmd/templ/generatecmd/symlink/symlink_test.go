@@ -1,11 +1,16 @@
 package symlink
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io"
 	"os"
 	"path"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/a-h/templ/cmd/templ/generatecmd"
 	"github.com/a-h/templ/cmd/templ/testproject"
@@ -54,3 +59,129 @@ func TestSymlink(t *testing.T) {
 		}
 	})
 }
+
+func TestSymlinkWorkingDirectory(t *testing.T) {
+	// cd symlink && templ generate -f templates.templ
+	dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+	if err != nil {
+		t.Fatalf("failed to create test project: %v", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Errorf("failed to remove test project directory: %v", err)
+		}
+	}()
+
+	symlinkPath := dir + "-symlink"
+	if err = os.Symlink(dir, symlinkPath); err != nil {
+		t.Fatalf("failed to create dir symlink: %v", err)
+	}
+	defer func() {
+		if err = os.Remove(symlinkPath); err != nil {
+			t.Errorf("failed to remove symlink directory: %v", err)
+		}
+	}()
+	t.Chdir(symlinkPath)
+
+	err = generatecmd.Run(context.Background(), io.Discard, io.Discard, []string{"-f", "templates.templ"})
+	if err != nil {
+		t.Fatalf("failed to run generate command: %v", err)
+	}
+
+	// The file name in the generated code is relative to the working directory.
+	code, err := os.ReadFile(path.Join(dir, "templates_templ.go"))
+	if err != nil {
+		t.Fatalf("failed to read templates_templ.go: %v", err)
+	}
+	if !strings.Contains(string(code), "FileName: `templates.templ`") {
+		t.Errorf("expected the generated code to refer to templates.templ, got:\n%s", code)
+	}
+}
+
+func TestSymlinkWatch(t *testing.T) {
+	t.Run("watches files if root is symlink", func(t *testing.T) {
+		dir, err := testproject.Create("github.com/a-h/templ/cmd/templ/testproject")
+		if err != nil {
+			t.Fatalf("failed to create test project: %v", err)
+		}
+		defer func() {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Errorf("failed to remove test project directory: %v", err)
+			}
+		}()
+
+		symlinkPath := dir + "-symlink"
+		err = os.Symlink(dir, symlinkPath)
+		if err != nil {
+			t.Fatalf("failed to create dir symlink: %v", err)
+		}
+		defer func() {
+			if err = os.Remove(symlinkPath); err != nil {
+				t.Errorf("failed to remove symlink directory: %v", err)
+			}
+		}()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		stderr := &syncBuffer{}
+		wg.Go(func() {
+			err := generatecmd.Run(ctx, io.Discard, stderr, []string{"-path", symlinkPath, "-watch"})
+			if err != nil && ctx.Err() == nil {
+				t.Errorf("failed to run generate command: %v", err)
+			}
+		})
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+
+		// Wait for the watcher to start, so that the new file isn't picked up
+		// by the initial walk.
+		if err := waitFor(func() bool {
+			return strings.Contains(stderr.String(), "Watching files")
+		}); err != nil {
+			t.Fatalf("watcher did not start: %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+
+		// Add a new file, and check that it's picked up by the watcher.
+		err = os.WriteFile(path.Join(symlinkPath, "added.templ"), []byte("package main\n\ntempl Added() {\n\t<div>Added</div>\n}\n"), 0644)
+		if err != nil {
+			t.Fatalf("failed to write added.templ: %v", err)
+		}
+		if err := waitFor(func() bool {
+			_, err := os.Stat(path.Join(symlinkPath, "added_templ.go"))
+			return err == nil
+		}); err != nil {
+			t.Fatalf("added_templ.go was not created: %v", err)
+		}
+	})
+}
+
+func waitFor(f func() bool) error {
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if f() {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return errors.New("timed out")
+}
+
+type syncBuffer struct {
+	m   sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (n int, err error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.buf.String()
+}
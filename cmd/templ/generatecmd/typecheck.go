@@ -0,0 +1,175 @@
+package generatecmd
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/parse"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/a-h/templ/cmd/templ/processor"
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/parser/v2"
+)
+
+// TypeError is an error found while type checking templ files.
+type TypeError struct {
+	FileName string
+	// Line and Col are 1-based, and are zero if the position is unknown.
+	Line    int
+	Col     int
+	Message string
+}
+
+func (e TypeError) String() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.FileName, e.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.FileName, e.Line, e.Col, e.Message)
+}
+
+type typeCheckFile struct {
+	templFileName string
+	sourceMap     *parser.SourceMap
+}
+
+// TypeCheck generates Go code for the templ files in memory, and type checks
+// the packages that contain them, without writing any files. Errors in
+// generated code are mapped back to their position in the templ file.
+func (cmd Generate) TypeCheck(ctx context.Context) (typeErrors []TypeError, err error) {
+	if err = cmd.init(); err != nil {
+		return nil, err
+	}
+
+	overlay := make(map[string][]byte)
+	goFileToTemplFile := make(map[string]typeCheckFile)
+	templFileNames := make(chan string)
+	walkErr := make(chan error, 1)
+	go func() {
+		defer close(templFileNames)
+		walkErr <- processor.FindTemplates(cmd.Args.Path, cmd.ShouldSkip, templFileNames)
+	}()
+	for fileName := range templFileNames {
+		goFileName, code, sourceMap, err := cmd.generateInMemory(fileName)
+		if err != nil {
+			typeErrors = append(typeErrors, parseTypeError(fileName, err))
+			continue
+		}
+		overlay[goFileName] = code
+		goFileToTemplFile[goFileName] = typeCheckFile{templFileName: fileName, sourceMap: sourceMap}
+	}
+	if err = <-walkErr; err != nil {
+		return nil, fmt.Errorf("failed to find templ files: %w", err)
+	}
+
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:     cmd.Args.Path,
+		Overlay: overlay,
+	}
+	if cmd.Args.Tags != "" {
+		cfg.BuildFlags = []string{"-tags=" + cmd.Args.Tags}
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	for _, pkg := range pkgs {
+		hasTypeErrors := slices.ContainsFunc(pkg.Errors, func(e packages.Error) bool {
+			return e.Kind == packages.TypeError
+		})
+		for _, e := range pkg.Errors {
+			// Compiling the package also reports type errors, but against the
+			// names of temporary overlay files, so they're skipped.
+			if hasTypeErrors && e.Kind == packages.ListError && e.Pos == "" {
+				continue
+			}
+			typeErrors = append(typeErrors, remapTypeError(e, goFileToTemplFile))
+		}
+	}
+	slices.SortFunc(typeErrors, func(a, b TypeError) int {
+		return cmp.Or(
+			cmp.Compare(a.FileName, b.FileName),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Col, b.Col),
+			cmp.Compare(a.Message, b.Message),
+		)
+	})
+	return slices.Compact(typeErrors), nil
+}
+
+// generateInMemory generates unformatted Go code for the templ file, so that
+// positions in the code match the source map.
+func (cmd Generate) generateInMemory(fileName string) (goFileName string, code []byte, sourceMap *parser.SourceMap, err error) {
+	t, err := parser.Parse(fileName)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	relFilePath, err := filepath.Rel(cmd.Args.Path, fileName)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to get relative path: %w", err)
+	}
+	var b bytes.Buffer
+	output, err := generator.Generate(t, &b, generator.WithFileName(filepath.ToSlash(relFilePath)))
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("generation error: %w", err)
+	}
+	goFileName = strings.TrimSuffix(fileName, ".templ") + "_templ.go"
+	return goFileName, b.Bytes(), output.SourceMap, nil
+}
+
+func parseTypeError(fileName string, err error) TypeError {
+	te := TypeError{FileName: fileName, Message: err.Error()}
+	var pe parse.ParseError
+	if errors.As(err, &pe) {
+		te.Line, te.Col, te.Message = pe.Pos.Line+1, pe.Pos.Col+1, pe.Msg
+	}
+	return te
+}
+
+func remapTypeError(e packages.Error, goFileToTemplFile map[string]typeCheckFile) TypeError {
+	te := TypeError{Message: e.Msg}
+	te.FileName, te.Line, te.Col = parseErrorPos(e.Pos)
+	f, ok := goFileToTemplFile[te.FileName]
+	if !ok || te.Line == 0 {
+		return te
+	}
+	// Source map lines are 0-based, while Go error positions are 1-based.
+	srcPos, ok := f.sourceMap.SourcePositionFromTarget(uint32(te.Line-1), uint32(te.Col))
+	if !ok {
+		return te
+	}
+	te.FileName = f.templFileName
+	te.Line = int(srcPos.Line) + 1
+	te.Col = int(srcPos.Col)
+	return te
+}
+
+// parseErrorPos parses a position in the form "file:line:col" or "file:line".
+func parseErrorPos(pos string) (fileName string, line, col int) {
+	fileName = pos
+	for range 2 {
+		i := strings.LastIndex(fileName, ":")
+		if i < 0 {
+			break
+		}
+		n, err := strconv.Atoi(fileName[i+1:])
+		if err != nil {
+			break
+		}
+		line, col = n, line
+		fileName = fileName[:i]
+	}
+	if line == 0 {
+		col = 0
+	}
+	return fileName, line, col
+}
@@ -0,0 +1,159 @@
+package generatecmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/cmd/templ/testproject"
+)
+
+func createTypeCheckProject(t *testing.T) (dir string) {
+	t.Helper()
+	moduleRoot, err := filepath.Abs("../../..")
+	if err != nil {
+		t.Fatalf("failed to get module root: %v", err)
+	}
+	dir, err = testproject.Create(moduleRoot)
+	if err != nil {
+		t.Fatalf("failed to create test project: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Errorf("failed to remove test project directory: %v", err)
+		}
+	})
+	// The test project includes a component that is used without being called.
+	// Fix it, so that the only errors are the ones added by each test.
+	remoteParent := path.Join(dir, "remoteparent.templ")
+	contents, err := os.ReadFile(remoteParent)
+	if err != nil {
+		t.Fatalf("failed to read remoteparent.templ: %v", err)
+	}
+	contents = bytes.ReplaceAll(contents, []byte("@Remote\n"), []byte("@Remote()\n"))
+	if err = os.WriteFile(remoteParent, contents, 0o644); err != nil {
+		t.Fatalf("failed to write remoteparent.templ: %v", err)
+	}
+	return dir
+}
+
+func TestTypeCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping type check tests in short mode")
+	}
+	t.Run("succeeds when there are no type errors", func(t *testing.T) {
+		dir := createTypeCheckProject(t)
+
+		err := Run(context.Background(), io.Discard, io.Discard, []string{"-path", dir, "-typecheck"})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	})
+	t.Run("reports type errors at their position in the templ file", func(t *testing.T) {
+		dir := createTypeCheckProject(t)
+		invalid := `package main
+
+templ Invalid(count int) {
+	<div>{ count + "1" }</div>
+	<div>{ undefinedName }</div>
+}
+`
+		if err := os.WriteFile(path.Join(dir, "invalid.templ"), []byte(invalid), 0o644); err != nil {
+			t.Fatalf("failed to write invalid.templ: %v", err)
+		}
+
+		stdout := &bytes.Buffer{}
+		err := Run(context.Background(), stdout, io.Discard, []string{"-path", dir, "-typecheck"})
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+
+		output := stdout.String()
+		if !strings.Contains(output, "invalid.templ:4:9: ") || !strings.Contains(output, "mismatched types") {
+			t.Errorf("expected a type mismatch error, got:\n%s", output)
+		}
+		if !strings.Contains(output, "invalid.templ:5:9: undefined: undefinedName") {
+			t.Errorf("expected an undefined name error, got:\n%s", output)
+		}
+		if _, err := os.Stat(path.Join(dir, "invalid_templ.go")); err == nil {
+			t.Error("expected invalid_templ.go not to be written")
+		}
+	})
+	t.Run("uses build tags", func(t *testing.T) {
+		dir := createTypeCheckProject(t)
+		files := map[string]string{
+			"tagged.go":      "//go:build custom\n\npackage main\n\nvar taggedValue = \"tagged\"\n",
+			"tagged.templ":   "//go:build custom\n\npackage main\n\ntempl Tagged() {\n\t<div>{ taggedValue }</div>\n}\n",
+			"untagged.templ": "package main\n\ntempl Untagged() {\n\t<div>{ taggedValue }</div>\n}\n",
+		}
+		for name, contents := range files {
+			if err := os.WriteFile(path.Join(dir, name), []byte(contents), 0o644); err != nil {
+				t.Fatalf("failed to write %s: %v", name, err)
+			}
+		}
+
+		stdout := &bytes.Buffer{}
+		err := Run(context.Background(), stdout, io.Discard, []string{"-path", dir, "-typecheck"})
+		if err == nil {
+			t.Fatal("expected an error without the build tag, got nil")
+		}
+		if !strings.Contains(stdout.String(), "untagged.templ:4:9: undefined: taggedValue") {
+			t.Errorf("expected an undefined name error, got:\n%s", stdout.String())
+		}
+
+		err = Run(context.Background(), io.Discard, io.Discard, []string{"-path", dir, "-typecheck", "-tags", "custom"})
+		if err != nil {
+			t.Fatalf("expected no error with the build tag, got: %v", err)
+		}
+	})
+	t.Run("supports projects that vendor their dependencies", func(t *testing.T) {
+		dir := createTypeCheckProject(t)
+		// Generate the code first, so that go mod vendor includes the templ runtime.
+		if err := Run(context.Background(), io.Discard, io.Discard, []string{"-path", dir}); err != nil {
+			t.Fatalf("failed to generate code: %v", err)
+		}
+		for _, args := range [][]string{{"mod", "tidy"}, {"mod", "vendor"}} {
+			cmd := exec.Command("go", args...)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GOFLAGS=")
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, output)
+			}
+		}
+		t.Setenv("GOFLAGS", "-mod=vendor")
+
+		err := Run(context.Background(), io.Discard, io.Discard, []string{"-path", dir, "-typecheck"})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+	})
+}
+
+func TestParseErrorPos(t *testing.T) {
+	tests := []struct {
+		pos          string
+		expectedFile string
+		expectedLine int
+		expectedCol  int
+	}{
+		{pos: "/a/b_templ.go:10:5", expectedFile: "/a/b_templ.go", expectedLine: 10, expectedCol: 5},
+		{pos: "/a/b_templ.go:10", expectedFile: "/a/b_templ.go", expectedLine: 10},
+		{pos: `C:\a\b_templ.go:10:5`, expectedFile: `C:\a\b_templ.go`, expectedLine: 10, expectedCol: 5},
+		{pos: "", expectedFile: ""},
+		{pos: "-", expectedFile: "-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pos, func(t *testing.T) {
+			file, line, col := parseErrorPos(tt.pos)
+			if file != tt.expectedFile || line != tt.expectedLine || col != tt.expectedCol {
+				t.Errorf("expected %s:%d:%d, got %s:%d:%d", tt.expectedFile, tt.expectedLine, tt.expectedCol, file, line, col)
+			}
+		})
+	}
+}
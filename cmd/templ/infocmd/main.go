@@ -14,11 +14,14 @@ import (
 	"sync"
 
 	"github.com/a-h/templ"
+	"github.com/a-h/templ/cmd/templ/listcmd"
 	"github.com/a-h/templ/cmd/templ/lspcmd/pls"
 )
 
 type Arguments struct {
-	JSON bool `flag:"json" help:"Output info as JSON."`
+	JSON       bool   `flag:"json" help:"Output info as JSON."`
+	Components bool   `flag:"components" help:"Include the components declared in templ files."`
+	Path       string `flag:"path" help:"Path to search for templ files."`
 }
 
 type Info struct {
@@ -30,6 +33,9 @@ type Info struct {
 	Gopls    ToolInfo `json:"gopls"`
 	Templ    ToolInfo `json:"templ"`
 	Prettier ToolInfo `json:"prettier"`
+	// Components are only included if requested, because finding them requires
+	// parsing every templ file.
+	Components []listcmd.Component `json:"components,omitempty"`
 }
 
 type ToolInfo struct {
@@ -168,6 +174,11 @@ func getInfo() (d Info) {
 
 func Run(ctx context.Context, log *slog.Logger, stdout io.Writer, args Arguments) (err error) {
 	info := getInfo()
+	if args.Components {
+		if info.Components, err = listcmd.List(ctx, log, args.Path); err != nil {
+			return err
+		}
+	}
 	if args.JSON {
 		enc := json.NewEncoder(stdout)
 		enc.SetIndent("", "  ")
@@ -178,9 +189,34 @@ func Run(ctx context.Context, log *slog.Logger, stdout io.Writer, args Arguments
 	logInfo(ctx, log, "gopls", info.Gopls)
 	logInfo(ctx, log, "templ", info.Templ)
 	logInfo(ctx, log, "prettier", info.Prettier)
+	for _, c := range info.Components {
+		logComponent(log, c)
+	}
 	return nil
 }
 
+func logComponent(log *slog.Logger, c listcmd.Component) {
+	pkg := c.ImportPath
+	if pkg == "" {
+		pkg = c.Package
+	}
+	args := []any{
+		slog.String("name", c.Name),
+		slog.String("package", pkg),
+	}
+	if c.Receiver != "" {
+		args = append(args, slog.String("receiver", c.Receiver))
+	}
+	args = append(args,
+		slog.String("params", c.Params),
+		slog.String("location", fmt.Sprintf("%s:%d", c.File, c.Line)),
+	)
+	if c.Doc != "" {
+		args = append(args, slog.String("doc", c.Doc))
+	}
+	log.Info("component", args...)
+}
+
 func logInfo(ctx context.Context, log *slog.Logger, name string, ti ToolInfo) {
 	args := []any{
 		slog.String("location", ti.Location),
@@ -0,0 +1,196 @@
+package listcmd
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strconv"
+	"strings"
+
+	templparser "github.com/a-h/templ/parser/v2"
+)
+
+// Component describes a templ component declaration.
+type Component struct {
+	Name       string            `json:"name"`
+	Package    string            `json:"package"`
+	ImportPath string            `json:"importPath,omitempty"`
+	Receiver   string            `json:"receiver,omitempty"`
+	Params     string            `json:"params"`
+	File       string            `json:"file"`
+	Line       int               `json:"line"`
+	Doc        string            `json:"doc,omitempty"`
+	Meta       map[string]string `json:"meta,omitempty"`
+}
+
+func (c Component) qualifiedName() string {
+	if c.Receiver == "" {
+		return c.Name
+	}
+	return c.Receiver + "." + c.Name
+}
+
+const metaDirective = "//templ:meta"
+
+// Components returns the components declared in the template file, along with
+// the doc comment and any //templ:meta annotations in the comment block
+// directly above each one.
+func Components(fileName string, tf *templparser.TemplateFile) (components []Component, err error) {
+	pkg := strings.TrimSpace(strings.TrimPrefix(tf.Package.Expression.Value, "package"))
+	var previous templparser.TemplateFileNode
+	for _, node := range tf.Nodes {
+		t, isTemplate := node.(*templparser.HTMLTemplate)
+		if !isTemplate {
+			previous = node
+			continue
+		}
+		c, err := parseSignature(t.Expression.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", fileName, t.Range.From.Line+1, err)
+		}
+		c.Package = pkg
+		c.File = fileName
+		c.Line = int(t.Range.From.Line) + 1
+		comments := commentAbove(previous, t.Range.From.Line)
+		c.Doc = parseDoc(comments)
+		c.Meta, err = parseMeta(comments)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", fileName, t.Range.From.Line+1, err)
+		}
+		components = append(components, c)
+		previous = node
+	}
+	return components, nil
+}
+
+// commentAbove returns the lines of the comment block that ends on the line
+// directly above the template declaration.
+func commentAbove(previous templparser.TemplateFileNode, templateLine uint32) (lines []string) {
+	goExpr, ok := previous.(*templparser.TemplateFileGoExpression)
+	if !ok {
+		return nil
+	}
+	src := strings.Split(goExpr.Expression.Value, "\n")
+	lastLine := goExpr.Expression.Range.From.Line + uint32(len(src)-1)
+	if lastLine+1 != templateLine {
+		return nil
+	}
+	for i := len(src) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(src[i])
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		lines = append([]string{line}, lines...)
+	}
+	return lines
+}
+
+// parseDoc returns the text of the comment lines, without comment markers
+// or directives such as //templ:meta and //go:generate.
+func parseDoc(comments []string) string {
+	var lines []string
+	for _, comment := range comments {
+		text := strings.TrimPrefix(comment, "//")
+		if isDirective(text) {
+			continue
+		}
+		lines = append(lines, strings.TrimPrefix(text, " "))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// isDirective reports whether the comment text, without the leading //, is a
+// directive, e.g. templ:meta or go:generate, as defined by go/ast.
+func isDirective(text string) bool {
+	colon := strings.Index(text, ":")
+	if colon <= 0 || colon+1 >= len(text) {
+		return false
+	}
+	for _, r := range text[:colon] {
+		if !('a' <= r && r <= 'z' || '0' <= r && r <= '9') {
+			return false
+		}
+	}
+	r := text[colon+1]
+	return 'a' <= r && r <= 'z' || '0' <= r && r <= '9'
+}
+
+// parseMeta parses //templ:meta key=value annotations. Values containing
+// spaces can be written as Go quoted strings, e.g. key="some value".
+func parseMeta(comments []string) (meta map[string]string, err error) {
+	for _, comment := range comments {
+		rest, ok := strings.CutPrefix(comment, metaDirective)
+		if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+			var key, value string
+			key, rest, ok = strings.Cut(rest, "=")
+			if !ok || key == "" || strings.ContainsAny(key, " \t") {
+				return nil, fmt.Errorf("invalid %s annotation %q: expected key=value", metaDirective, comment)
+			}
+			if strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, "`") {
+				quoted, err := strconv.QuotedPrefix(rest)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s annotation %q: %w", metaDirective, comment, err)
+				}
+				value, _ = strconv.Unquote(quoted)
+				rest = rest[len(quoted):]
+			} else {
+				end := strings.IndexAny(rest, " \t")
+				if end < 0 {
+					end = len(rest)
+				}
+				value, rest = rest[:end], rest[end:]
+			}
+			if meta == nil {
+				meta = make(map[string]string)
+			}
+			meta[key] = value
+		}
+	}
+	return meta, nil
+}
+
+func quoteIfNeeded(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\"`") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// parseSignature parses the name, receiver and parameters from a template
+// declaration expression, e.g. "(b Button) Render(label string)".
+func parseSignature(expr string) (c Component, err error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", "package p\nfunc "+expr+" {}", parser.SkipObjectResolution)
+	if err != nil {
+		return c, fmt.Errorf("failed to parse template signature %q: %w", expr, err)
+	}
+	fn, ok := f.Decls[0].(*ast.FuncDecl)
+	if !ok {
+		return c, fmt.Errorf("failed to parse template signature %q", expr)
+	}
+	c.Name = fn.Name.Name
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		c.Receiver = nodeString(fset, fn.Recv.List[0].Type)
+	}
+	params := make([]string, len(fn.Type.Params.List))
+	for i, p := range fn.Type.Params.List {
+		names := make([]string, len(p.Names))
+		for j, n := range p.Names {
+			names[j] = n.Name
+		}
+		params[i] = strings.TrimSpace(strings.Join(names, ", ") + " " + nodeString(fset, p.Type))
+	}
+	c.Params = strings.Join(params, ", ")
+	return c, nil
+}
+
+func nodeString(fset *token.FileSet, n ast.Node) string {
+	var sb strings.Builder
+	_ = printer.Fprint(&sb, fset, n)
+	return sb.String()
+}
@@ -0,0 +1,171 @@
+package listcmd
+
+import (
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestComponents(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Component
+	}{
+		{
+			name: "components without annotations have no metadata",
+			input: `package main
+
+templ Hello(name string) {
+	<div>{ name }</div>
+}
+`,
+			expected: []Component{
+				{Name: "Hello", Package: "main", Params: "name string", File: "test.templ", Line: 3},
+			},
+		},
+		{
+			name: "meta annotations are read from the comment above the component",
+			input: `package main
+
+import "fmt"
+
+// Button renders a button.
+//templ:meta owner=ui status=beta
+//templ:meta spec="https://example.com/design system"
+templ (b Button) Render(label string, count int) {
+	<button>{ fmt.Sprint(count) }</button>
+}
+
+//templ:meta owner=platform
+
+templ Detached() {
+	<div></div>
+}
+`,
+			expected: []Component{
+				{
+					Name:     "Render",
+					Package:  "main",
+					Receiver: "Button",
+					Params:   "label string, count int",
+					File:     "test.templ",
+					Line:     8,
+					Doc:      "Button renders a button.",
+					Meta: map[string]string{
+						"owner":  "ui",
+						"status": "beta",
+						"spec":   "https://example.com/design system",
+					},
+				},
+				{Name: "Detached", Package: "main", File: "test.templ", Line: 14},
+			},
+		},
+		{
+			name: "doc comments are read from the comment above the component",
+			input: `package main
+
+// Card renders a card.
+//
+// The title is shown in a heading.
+//templ:meta owner=ui
+//go:generate echo
+templ Card(title string) {
+	<div>{ title }</div>
+}
+`,
+			expected: []Component{
+				{
+					Name:    "Card",
+					Package: "main",
+					Params:  "title string",
+					File:    "test.templ",
+					Line:    8,
+					Doc:     "Card renders a card.\n\nThe title is shown in a heading.",
+					Meta:    map[string]string{"owner": "ui"},
+				},
+			},
+		},
+		{
+			name: "generic components are supported",
+			input: `package main
+
+//templ:meta owner=ui
+templ List[T any](items []T) {
+	<ul></ul>
+}
+`,
+			expected: []Component{
+				{Name: "List", Package: "main", Params: "items []T", File: "test.templ", Line: 4, Meta: map[string]string{"owner": "ui"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := parser.ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			actual, err := Components("test.templ", tf)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseMeta(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       []string
+		expected    map[string]string
+		expectedErr bool
+	}{
+		{
+			name:     "other comments are ignored",
+			input:    []string{"// Component docs.", "//templ:metadata x=y"},
+			expected: nil,
+		},
+		{
+			name:     "later values override earlier ones",
+			input:    []string{"//templ:meta owner=a", "//templ:meta owner=b"},
+			expected: map[string]string{"owner": "b"},
+		},
+		{
+			name:     "values can contain equals signs",
+			input:    []string{"//templ:meta link=https://example.com/?a=b"},
+			expected: map[string]string{"link": "https://example.com/?a=b"},
+		},
+		{
+			name:        "values must have a key",
+			input:       []string{"//templ:meta beta"},
+			expectedErr: true,
+		},
+		{
+			name:        "quoted values must be terminated",
+			input:       []string{`//templ:meta spec="unterminated`},
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := parseMeta(tt.input)
+			if tt.expectedErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package listcmd
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/a-h/templ/cmd/templ/processor"
+	"github.com/a-h/templ/parser/v2"
+	"golang.org/x/mod/modfile"
+)
+
+type Arguments struct {
+	Path string `flag:"path" help:"Path to search for templ files."`
+	JSON bool   `flag:"json" help:"Output components as JSON."`
+}
+
+func Run(ctx context.Context, log *slog.Logger, stdout io.Writer, args Arguments) (err error) {
+	components, err := List(ctx, log, args.Path)
+	if err != nil {
+		return err
+	}
+	if args.JSON {
+		if components == nil {
+			components = []Component{}
+		}
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(components)
+	}
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	for _, c := range components {
+		_, _ = fmt.Fprintf(w, "%s:%d\t%s\t%s\n", c.File, c.Line, c.qualifiedName(), formatMeta(c.Meta))
+	}
+	return w.Flush()
+}
+
+// List returns the components declared in the templ files within path,
+// ordered by file name and line.
+func List(ctx context.Context, log *slog.Logger, path string) (components []Component, err error) {
+	if path == "" {
+		path = "."
+	}
+	fileNames := make(chan string)
+	walkErr := make(chan error, 1)
+	go func() {
+		defer close(fileNames)
+		walkErr <- processor.FindTemplates(path, nil, fileNames)
+	}()
+	importPaths := make(importPathCache)
+	for fileName := range fileNames {
+		if ctx.Err() != nil {
+			continue
+		}
+		fileComponents, err := listFile(fileName)
+		if err != nil {
+			log.Warn("Skipping file", slog.String("file", fileName), slog.Any("error", err))
+			continue
+		}
+		importPath := importPaths.get(filepath.Dir(fileName))
+		for i := range fileComponents {
+			fileComponents[i].ImportPath = importPath
+		}
+		components = append(components, fileComponents...)
+	}
+	if err = <-walkErr; err != nil {
+		return nil, fmt.Errorf("failed to find templ files in %q: %w", path, err)
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(components, func(a, b Component) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+	})
+	return components, nil
+}
+
+func listFile(fileName string) (components []Component, err error) {
+	contents, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	tf, err := parser.ParseString(string(contents))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file: %w", err)
+	}
+	return Components(filepath.ToSlash(fileName), tf)
+}
+
+// importPathCache maps directories to Go import paths, using the module path
+// in the nearest go.mod file.
+type importPathCache map[string]string
+
+// get returns the import path of the package in dir, or an empty string if
+// dir isn't within a module.
+func (c importPathCache) get(dir string) (importPath string) {
+	if importPath, ok := c[dir]; ok {
+		return importPath
+	}
+	defer func() { c[dir] = importPath }()
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for modDir := abs; ; {
+		data, err := os.ReadFile(filepath.Join(modDir, "go.mod"))
+		if err == nil {
+			modulePath := modfile.ModulePath(data)
+			if modulePath == "" {
+				return ""
+			}
+			rel, err := filepath.Rel(modDir, abs)
+			if err != nil {
+				return ""
+			}
+			return path.Join(modulePath, filepath.ToSlash(rel))
+		}
+		parent := filepath.Dir(modDir)
+		if parent == modDir {
+			return ""
+		}
+		modDir = parent
+	}
+}
+
+func formatMeta(meta map[string]string) string {
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var sb strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(k)
+		sb.WriteString("=")
+		sb.WriteString(quoteIfNeeded(meta[k]))
+	}
+	return sb.String()
+}
@@ -0,0 +1,66 @@
+package listcmd
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.25.0\n",
+		"components/button.templ": `package components
+
+templ Button(label string) {
+	<button>{ label }</button>
+}
+`,
+		"page.templ": `package main
+
+templ Page() {
+	@components.Button("OK")
+}
+`,
+	}
+	for name, contents := range files {
+		fileName := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fileName, []byte(contents), 0o644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	actual, err := List(context.Background(), log, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Component{
+		{
+			Name:       "Button",
+			Package:    "components",
+			ImportPath: "example.com/app/components",
+			Params:     "label string",
+			File:       filepath.ToSlash(filepath.Join(dir, "components", "button.templ")),
+			Line:       3,
+		},
+		{
+			Name:       "Page",
+			Package:    "main",
+			ImportPath: "example.com/app",
+			File:       filepath.ToSlash(filepath.Join(dir, "page.templ")),
+			Line:       3,
+		},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
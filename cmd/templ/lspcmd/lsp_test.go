@@ -759,6 +759,67 @@ func TestDocumentSymbol(t *testing.T) {
 	}
 }
 
+func TestWorkspaceSymbol(t *testing.T) {
+	if testing.Short() {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	testOutput := bytes.NewBuffer(nil)
+	log := slog.New(slog.NewJSONHandler(testOutput, nil))
+	defer func() {
+		if t.Failed() {
+			fmt.Println(testOutput.String())
+		}
+	}()
+
+	ctx, appDir, _, server, teardown, err := Setup(ctx, log, Arguments{})
+	if err != nil {
+		t.Fatalf("failed to setup test: %v", err)
+	}
+	defer teardown(t)
+	defer cancel()
+
+	tests := []struct {
+		query string
+		uri   string
+	}{
+		{
+			query: "Page",
+			uri:   "file://" + appDir + "/templates.templ",
+		},
+		{
+			query: "RemoteInclusionTest",
+			uri:   "file://" + appDir + "/remoteparent.templ",
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(fmt.Sprintf("test-%d", i), func(t *testing.T) {
+			var actual []protocol.SymbolInformation
+			// Give gopls time to index the workspace.
+			for range 3 {
+				actual, err = server.Symbols(ctx, &protocol.WorkspaceSymbolParams{
+					Query: test.query,
+				})
+				if err != nil {
+					t.Fatalf("failed to get workspace symbols: %v", err)
+				}
+				if len(actual) > 0 {
+					break
+				}
+				time.Sleep(time.Millisecond * 500)
+			}
+			for _, s := range actual {
+				if s.Name == test.query && string(s.Location.URI) == test.uri {
+					return
+				}
+			}
+			t.Errorf("expected symbol %q in %q, got %#v", test.query, test.uri, actual)
+		})
+	}
+}
+
 func TestFormatting(t *testing.T) {
 	if testing.Short() {
 		return
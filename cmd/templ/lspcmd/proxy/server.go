@@ -3,11 +3,18 @@ package proxy
 import (
 	"context"
 	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"unicode/utf16"
 
 	"github.com/a-h/parse"
 	"github.com/a-h/templ/internal/format"
@@ -46,6 +53,9 @@ type Server struct {
 	GoplsVersion       string
 	NoPreload          bool
 	preLoadURIs        []*lsp.DidOpenTextDocumentParams
+	preloadedMutex     sync.Mutex
+	preloaded          map[lsp.DocumentURI]struct{}
+	workspaceFolders   []lsp.WorkspaceFolder
 	templDocLazyLoader lazyloader.TemplDocLazyLoader
 	formatConf         format.Config
 }
@@ -59,6 +69,7 @@ func NewServer(log *slog.Logger, target lsp.Server, cache *SourceMapCache, diagn
 		TemplSource:     newDocumentContents(log),
 		GoSource:        make(map[string]string),
 		NoPreload:       noPreload,
+		preloaded:       make(map[lsp.DocumentURI]struct{}),
 		formatConf:      formatConf,
 	}
 }
@@ -112,12 +123,16 @@ func (p *Server) convertTemplRangeToGoRange(templURI lsp.DocumentURI, input lsp.
 }
 
 func (p *Server) convertGoRangeToTemplRange(templURI lsp.DocumentURI, input lsp.Range) (output lsp.Range) {
-	output = input
 	sourceMap, ok := p.SourceMapCache.Get(string(templURI))
 	if !ok {
 		p.Log.Warn("go->templ: sourcemap not found in cache")
-		return
+		return input
 	}
+	return p.convertGoRangeWithSourceMap(sourceMap, input)
+}
+
+func (p *Server) convertGoRangeWithSourceMap(sourceMap *parser.SourceMap, input lsp.Range) (output lsp.Range) {
+	output = input
 	// Map from the source position to target Go position.
 	start, startPositionMapped := sourceMap.SourcePositionFromTarget(input.Start.Line, input.Start.Character)
 	if startPositionMapped {
@@ -256,7 +271,10 @@ func (p *Server) Initialize(ctx context.Context, params *lsp.InitializeParams) (
 			OpenDocSources:  p.GoSource,
 		})
 	} else {
-		p.preload(ctx, params.WorkspaceFolders)
+		p.preloadedMutex.Lock()
+		p.workspaceFolders = slices.Clone(params.WorkspaceFolders)
+		p.preloadedMutex.Unlock()
+		p.preLoadURIs = p.preload(ctx, params.WorkspaceFolders)
 	}
 
 	result.ServerInfo.Name = "templ-lsp"
@@ -265,7 +283,9 @@ func (p *Server) Initialize(ctx context.Context, params *lsp.InitializeParams) (
 	return result, err
 }
 
-func (p *Server) preload(ctx context.Context, workspaceFolders []lsp.WorkspaceFolder) {
+// preload reads and generates the templ files in the workspace folders, and returns the
+// generated Go code to send to gopls.
+func (p *Server) preload(ctx context.Context, workspaceFolders []lsp.WorkspaceFolder) (preloaded []*lsp.DidOpenTextDocumentParams) {
 	for _, c := range workspaceFolders {
 		path, err := uri.ParseDocumentURI(c.URI)
 		if err != nil {
@@ -284,6 +304,10 @@ func (p *Server) preload(ctx context.Context, workspaceFolders []lsp.WorkspaceFo
 			if !isTemplFile {
 				return nil
 			}
+			if _, isOpen := p.TemplSource.Get(string(uri)); isOpen {
+				// Don't overwrite the contents of documents that the editor already has open.
+				return nil
+			}
 
 			b, err := os.ReadFile(path)
 			if err != nil {
@@ -318,13 +342,17 @@ func (p *Server) preload(ctx context.Context, workspaceFolders []lsp.WorkspaceFo
 				},
 			}
 
-			p.preLoadURIs = append(p.preLoadURIs, didOpenParams)
+			p.preloadedMutex.Lock()
+			p.preloaded[uri] = struct{}{}
+			p.preloadedMutex.Unlock()
+			preloaded = append(preloaded, didOpenParams)
 			return nil
 		})
 		if werr != nil {
 			p.Log.Error("walk error", slog.Any("error", werr))
 		}
 	}
+	return preloaded
 }
 
 func (p *Server) Initialized(ctx context.Context, params *lsp.InitializedParams) (err error) {
@@ -334,17 +362,71 @@ func (p *Server) Initialized(ctx context.Context, params *lsp.InitializedParams)
 
 	p.notifyGoplsVersion(ctx)
 
-	for i, doParams := range p.preLoadURIs {
-		doErr := p.Target.DidOpen(ctx, doParams)
-		if doErr != nil {
-			return doErr
-		}
-		p.preLoadURIs[i] = nil
+	preloaded := p.preLoadURIs
+	p.preLoadURIs = nil
+	if err = p.openPreloaded(ctx, preloaded); err != nil {
+		return err
 	}
 
 	return goInitErr
 }
 
+// openPreloaded sends the generated Go code of preloaded templ files to gopls.
+func (p *Server) openPreloaded(ctx context.Context, preloaded []*lsp.DidOpenTextDocumentParams) (err error) {
+	for _, doParams := range preloaded {
+		if err = p.Target.DidOpen(ctx, doParams); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closePreloaded closes the templ files that were preloaded from the removed workspace
+// folders, unless they have since been opened by the editor, or are within one of the
+// remaining workspace folders.
+func (p *Server) closePreloaded(ctx context.Context, removed []lsp.WorkspaceFolder) (err error) {
+	var closed []lsp.DocumentURI
+	p.preloadedMutex.Lock()
+	p.workspaceFolders = slices.DeleteFunc(p.workspaceFolders, func(f lsp.WorkspaceFolder) bool {
+		return slices.ContainsFunc(removed, func(r lsp.WorkspaceFolder) bool { return r.URI == f.URI })
+	})
+	for templURI := range p.preloaded {
+		if !p.inWorkspaceFolder(removed, templURI) || p.inWorkspaceFolder(p.workspaceFolders, templURI) {
+			continue
+		}
+		delete(p.preloaded, templURI)
+		closed = append(closed, templURI)
+	}
+	p.preloadedMutex.Unlock()
+	for _, templURI := range closed {
+		p.TemplSource.Delete(string(templURI))
+		p.SourceMapCache.Delete(string(templURI))
+		delete(p.GoSource, string(templURI))
+		_, goURI := convertTemplToGoURI(templURI)
+		if err = p.Target.DidClose(ctx, &lsp.DidCloseTextDocumentParams{
+			TextDocument: lsp.TextDocumentIdentifier{URI: goURI},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inWorkspaceFolder returns true if the document is within any of the workspace folders.
+func (p *Server) inWorkspaceFolder(workspaceFolders []lsp.WorkspaceFolder, documentURI lsp.DocumentURI) bool {
+	for _, c := range workspaceFolders {
+		folder, err := uri.ParseDocumentURI(c.URI)
+		if err != nil {
+			p.Log.Error("invalid uri", slog.String("uri", c.URI))
+			continue
+		}
+		if strings.HasPrefix(documentURI.Filename(), folder.Filename()+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *Server) notifyGoplsVersion(ctx context.Context) {
 	client := lsp.ClientFromContext(ctx)
 	if client == nil {
@@ -415,17 +497,18 @@ func (p *Server) CodeAction(ctx context.Context, params *lsp.CodeActionParams) (
 	if !isTemplFile {
 		return p.Target.CodeAction(ctx, params)
 	}
+	// Organizing imports applies to the whole file, so it doesn't depend on the range.
+	updatedResults := p.organizeImportsCodeAction(templURI, params.Context.Only)
 	var ok bool
 	if params.Range, ok = p.convertTemplRangeToGoRange(templURI, params.Range); !ok {
 		// Don't pass the request to gopls if the range is not within a Go code block.
-		return
+		return updatedResults, nil
 	}
 	params.TextDocument.URI = goURI
 	result, err = p.Target.CodeAction(ctx, params)
 	if err != nil {
 		return
 	}
-	var updatedResults []lsp.CodeAction
 	// Filter out commands that are not yet supported.
 	// For example, "Fill Struct" runs the `gopls.apply_fix` command.
 	// This command has a set of arguments, including Fix, Range and URI.
@@ -454,6 +537,127 @@ func (p *Server) CodeAction(ctx context.Context, params *lsp.CodeActionParams) (
 	return updatedResults, nil
 }
 
+// wantsCodeActionKind returns true if the client explicitly requested the kind of code action.
+// Source actions apply to the whole file, so they're not computed for ordinary requests that
+// don't list the kinds they want.
+func wantsCodeActionKind(only []lsp.CodeActionKind, kind lsp.CodeActionKind) bool {
+	for _, k := range only {
+		if k == kind || strings.HasPrefix(string(kind), string(k)+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// organizeImportsCodeAction returns a source.organizeImports action that sorts, groups and
+// removes unused imports in the templ file, or nil if the imports are already organized.
+// Only the import declarations are edited, the rest of the file is left as it is.
+func (p *Server) organizeImportsCodeAction(templURI uri.URI, only []lsp.CodeActionKind) (result []lsp.CodeAction) {
+	if !wantsCodeActionKind(only, lsp.SourceOrganizeImports) {
+		return nil
+	}
+	d, ok := p.TemplSource.Get(string(templURI))
+	if !ok {
+		return nil
+	}
+	text := d.String()
+	template, err := parser.ParseString(text)
+	if err != nil {
+		// Parse errors are reported as diagnostics when the document changes.
+		return nil
+	}
+	template.Filepath = string(templURI)
+	from, to, oldImports := findImports(template)
+	template, err = imports.Process(template)
+	if err != nil {
+		p.Log.Error("organise imports failure", slog.Any("error", err))
+		return nil
+	}
+	_, _, newImports := findImports(template)
+	if newImports == oldImports {
+		return nil
+	}
+	if oldImports == "" {
+		// Add the imports after the package declaration.
+		from = int(template.Package.Expression.Range.To.Index)
+		to = from
+		newImports = "\n\n" + newImports
+	} else if newImports == "" {
+		// Remove the blank lines that followed the imports.
+		to += len(text[to:]) - len(strings.TrimLeft(text[to:], " \t\r\n"))
+	}
+	return []lsp.CodeAction{
+		{
+			Title: "Organize Imports",
+			Kind:  lsp.SourceOrganizeImports,
+			Edit: &lsp.WorkspaceEdit{
+				Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+					templURI: {
+						{
+							Range: lsp.Range{
+								Start: positionAtIndex(text, from),
+								End:   positionAtIndex(text, to),
+							},
+							NewText: newImports,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// findImports returns the byte range and text of the import declarations at the start of the
+// templ file, or an empty string if there are none.
+func findImports(template *parser.TemplateFile) (from, to int, text string) {
+	if len(template.Nodes) == 0 {
+		return 0, 0, ""
+	}
+	goExpr, ok := template.Nodes[0].(*parser.TemplateFileGoExpression)
+	if !ok {
+		return 0, 0, ""
+	}
+	const prefix = "package p\n"
+	src := prefix + goExpr.Expression.Value
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ImportsOnly|goparser.ParseComments)
+	if err != nil || len(f.Imports) == 0 {
+		return 0, 0, ""
+	}
+	var start, end token.Pos
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			break
+		}
+		if start == 0 {
+			start = gd.Pos()
+		}
+		end = gd.End()
+	}
+	// Positions are 1-based offsets into src.
+	startOffset, endOffset := int(start)-1-len(prefix), int(end)-1-len(prefix)
+	base := int(goExpr.Expression.Range.From.Index)
+	return base + startOffset, base + endOffset, goExpr.Expression.Value[startOffset:endOffset]
+}
+
+// positionAtIndex returns the LSP position of the byte index in the text.
+func positionAtIndex(text string, index int) lsp.Position {
+	before := text[:index]
+	line := strings.Count(before, "\n")
+	lineStart := strings.LastIndex(before, "\n") + 1
+	return lsp.Position{
+		Line:      uint32(line),
+		Character: uint32(utf16Len(before[lineStart:])),
+	}
+}
+
+func utf16Len(s string) (n int) {
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
 func (p *Server) CodeLens(ctx context.Context, params *lsp.CodeLensParams) (result []lsp.CodeLens, err error) {
 	p.Log.Info("client -> server: CodeLens")
 	defer p.Log.Info("client -> server: CodeLens end")
@@ -805,7 +1009,20 @@ func (p *Server) DidChangeWatchedFiles(ctx context.Context, params *lsp.DidChang
 func (p *Server) DidChangeWorkspaceFolders(ctx context.Context, params *lsp.DidChangeWorkspaceFoldersParams) (err error) {
 	p.Log.Info("client -> server: DidChangeWorkspaceFolders")
 	defer p.Log.Info("client -> server: DidChangeWorkspaceFolders end")
-	return p.Target.DidChangeWorkspaceFolders(ctx, params)
+	if err = p.Target.DidChangeWorkspaceFolders(ctx, params); err != nil {
+		return err
+	}
+	if p.NoPreload {
+		return nil
+	}
+	if err = p.closePreloaded(ctx, params.Event.Removed); err != nil {
+		return err
+	}
+	p.preloadedMutex.Lock()
+	p.workspaceFolders = append(p.workspaceFolders, params.Event.Added...)
+	p.preloadedMutex.Unlock()
+	// Preload the templ files in added folders, as is done for the initial folders.
+	return p.openPreloaded(ctx, p.preload(ctx, params.Event.Added))
 }
 
 func (p *Server) DidClose(ctx context.Context, params *lsp.DidCloseTextDocumentParams) (err error) {
@@ -872,6 +1089,10 @@ func (p *Server) HandleDidOpen(ctx context.Context, params *lsp.DidOpenTextDocum
 	if !isTemplFile {
 		return p.Target.DidOpen(ctx, params)
 	}
+	// The editor now manages the document, even if it was preloaded.
+	p.preloadedMutex.Lock()
+	delete(p.preloaded, templURI)
+	p.preloadedMutex.Unlock()
 	// Cache the template doc.
 	p.TemplSource.Set(string(templURI), NewDocument(p.Log, params.TextDocument.Text))
 	// Parse the template.
@@ -1302,7 +1523,114 @@ func (p *Server) References(ctx context.Context, params *lsp.ReferenceParams) (r
 func (p *Server) Rename(ctx context.Context, params *lsp.RenameParams) (result *lsp.WorkspaceEdit, err error) {
 	p.Log.Info("client -> server: Rename")
 	defer p.Log.Info("client -> server: Rename end")
-	return p.Target.Rename(ctx, params)
+	templURI, err := uri.ParseDocumentURI(string(params.TextDocument.URI))
+	if err != nil {
+		p.Log.Error("invalid uri", slog.String("uri", string(params.TextDocument.URI)))
+		return
+	}
+	// Rewrite the request.
+	var ok bool
+	ok, params.TextDocument.URI, params.Position = p.updatePosition(templURI, params.Position)
+	if !ok {
+		return nil, nil
+	}
+	// Call gopls.
+	result, err = p.Target.Rename(ctx, params)
+	if err != nil {
+		return
+	}
+	if result == nil {
+		return
+	}
+	// Rewrite the response.
+	if err = p.convertWorkspaceEdit(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// convertWorkspaceEdit rewrites edits to generated _templ.go files into edits
+// to the templ files they were generated from.
+func (p *Server) convertWorkspaceEdit(edit *lsp.WorkspaceEdit) error {
+	if edit.Changes != nil {
+		changes := make(map[lsp.DocumentURI][]lsp.TextEdit, len(edit.Changes))
+		for goURI, edits := range edit.Changes {
+			templURI, edits, err := p.convertTextEdits(goURI, edits)
+			if err != nil {
+				return err
+			}
+			if len(edits) == 0 {
+				continue
+			}
+			changes[templURI] = append(changes[templURI], edits...)
+		}
+		edit.Changes = changes
+	}
+	documentChanges := edit.DocumentChanges[:0]
+	for _, dc := range edit.DocumentChanges {
+		templURI, edits, err := p.convertTextEdits(dc.TextDocument.URI, dc.Edits)
+		if err != nil {
+			return err
+		}
+		if len(edits) == 0 {
+			continue
+		}
+		if templURI != dc.TextDocument.URI {
+			// The version is the version of the Go file, which means nothing to the client.
+			dc.TextDocument.Version = nil
+		}
+		dc.TextDocument.URI, dc.Edits = templURI, edits
+		documentChanges = append(documentChanges, dc)
+	}
+	edit.DocumentChanges = documentChanges
+	return nil
+}
+
+// convertTextEdits maps text edits in a generated _templ.go file to the templ file.
+// Edits to generated code that doesn't come from the templ file are dropped.
+func (p *Server) convertTextEdits(goURI lsp.DocumentURI, edits []lsp.TextEdit) (lsp.DocumentURI, []lsp.TextEdit, error) {
+	isTemplURI, templURI := convertTemplGoToTemplURI(goURI)
+	if !isTemplURI {
+		return goURI, edits, nil
+	}
+	sourceMap, err := p.loadSourceMap(templURI)
+	if err != nil {
+		return goURI, nil, err
+	}
+	output := make([]lsp.TextEdit, 0, len(edits))
+	for _, e := range edits {
+		start, startOK := sourceMap.SourcePositionFromTarget(e.Range.Start.Line, e.Range.Start.Character)
+		end, endOK := sourceMap.SourcePositionFromTarget(e.Range.End.Line, e.Range.End.Character)
+		// The source map searches backwards along the line when a position isn't mapped,
+		// so check that the range maps to a range of the same length.
+		if !startOK || !endOK || start.Line != end.Line || end.Col-start.Col != e.Range.End.Character-e.Range.Start.Character {
+			p.Log.Warn("go->templ: edit not found in sourcemap", slog.Any("range", e.Range))
+			continue
+		}
+		e.Range.Start = lsp.Position{Line: start.Line, Character: start.Col}
+		e.Range.End = lsp.Position{Line: end.Line, Character: end.Col}
+		output = append(output, e)
+	}
+	return templURI, output, nil
+}
+
+// loadSourceMap returns the source map of a templ file. Files that haven't been loaded,
+// for example because preloading is disabled, are read from disk and generated, so that
+// positions in their generated code can still be mapped.
+func (p *Server) loadSourceMap(templURI lsp.DocumentURI) (*parser.SourceMap, error) {
+	if sourceMap, ok := p.SourceMapCache.Get(string(templURI)); ok {
+		return sourceMap, nil
+	}
+	p.Log.Info("go->templ: sourcemap not found in cache, generating from file", slog.String("uri", string(templURI)))
+	template, err := parser.Parse(templURI.Filename())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %q to map edits to generated code: %w", templURI.Filename(), err)
+	}
+	generatorOutput, err := generator.Generate(template, io.Discard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %q to map edits to generated code: %w", templURI.Filename(), err)
+	}
+	return generatorOutput.SourceMap, nil
 }
 
 func (p *Server) SignatureHelp(ctx context.Context, params *lsp.SignatureHelpParams) (result *lsp.SignatureHelp, err error) {
@@ -1324,7 +1652,37 @@ func (p *Server) SignatureHelp(ctx context.Context, params *lsp.SignatureHelpPar
 func (p *Server) Symbols(ctx context.Context, params *lsp.WorkspaceSymbolParams) (result []lsp.SymbolInformation, err error) {
 	p.Log.Info("client -> server: Symbols")
 	defer p.Log.Info("client -> server: Symbols end")
-	return p.Target.Symbols(ctx, params)
+	result, err = p.Target.Symbols(ctx, params)
+	if err != nil {
+		return
+	}
+	// Templ components, css templates and script templates are found by gopls in the
+	// generated *_templ.go files, so point the results back at the templ files.
+	// Files that haven't been loaded are generated once, for all of their symbols.
+	sourceMaps := make(map[lsp.DocumentURI]*parser.SourceMap)
+	updated := result[:0]
+	for _, r := range result {
+		isTemplGoFile, templURI := convertTemplGoToTemplURI(r.Location.URI)
+		if !isTemplGoFile {
+			updated = append(updated, r)
+			continue
+		}
+		sourceMap, ok := sourceMaps[templURI]
+		if !ok {
+			if sourceMap, err = p.loadSourceMap(templURI); err != nil {
+				// Without a sourcemap, the symbol can't be located in the templ file.
+				p.Log.Warn("symbols: failed to load sourcemap, skipping symbol", slog.String("uri", string(templURI)), slog.Any("error", err))
+			}
+			sourceMaps[templURI] = sourceMap
+		}
+		if sourceMap == nil {
+			continue
+		}
+		r.Location.URI = templURI
+		r.Location.Range = p.convertGoRangeWithSourceMap(sourceMap, r.Location.Range)
+		updated = append(updated, r)
+	}
+	return updated, nil
 }
 
 func (p *Server) TypeDefinition(ctx context.Context, params *lsp.TypeDefinitionParams) (result []lsp.Location, err error) {
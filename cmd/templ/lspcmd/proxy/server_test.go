@@ -0,0 +1,462 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/generator"
+	"github.com/a-h/templ/internal/format"
+	lsp "github.com/a-h/templ/lsp/protocol"
+	"github.com/a-h/templ/lsp/uri"
+	"github.com/a-h/templ/parser/v2"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConvertWorkspaceEdit(t *testing.T) {
+	templContents := `package main
+
+templ Button(label string) {
+	<button>{ label }</button>
+}
+
+templ Page() {
+	@Button("ok")
+}
+`
+	tf, err := parser.ParseString(templContents)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	var goCode strings.Builder
+	output, err := generator.Generate(tf, &goCode)
+	if err != nil {
+		t.Fatalf("failed to generate Go code: %v", err)
+	}
+	cache := NewSourceMapCache()
+	cache.Set("file:///app/button.templ", output.SourceMap)
+	p := &Server{
+		Log:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+		SourceMapCache: cache,
+	}
+
+	// goRange returns the range of name at the first occurrence of s in the generated Go code.
+	goRange := func(s, name string) lsp.Range {
+		for i, line := range strings.Split(goCode.String(), "\n") {
+			if col := strings.Index(line, s); col >= 0 {
+				return lsp.Range{
+					Start: lsp.Position{Line: uint32(i), Character: uint32(col)},
+					End:   lsp.Position{Line: uint32(i), Character: uint32(col + len(name))},
+				}
+			}
+		}
+		t.Fatalf("%q not found in generated code", s)
+		return lsp.Range{}
+	}
+	templRange := func(line, col, length uint32) lsp.Range {
+		return lsp.Range{
+			Start: lsp.Position{Line: line, Character: col},
+			End:   lsp.Position{Line: line, Character: col + length},
+		}
+	}
+	mainGoEdit := lsp.TextEdit{Range: templRange(10, 4, 6), NewText: "Submit"}
+	goEdits := []lsp.TextEdit{
+		{Range: goRange("Button(label", "Button"), NewText: "Submit"},
+		{Range: goRange(`Button("ok")`, "Button"), NewText: "Submit"},
+		// Generated code that doesn't come from the templ file.
+		{Range: goRange("GeneratedTemplate", "GeneratedTemplate"), NewText: "Submit"},
+	}
+	expectedTemplEdits := []lsp.TextEdit{
+		{Range: templRange(2, 6, 6), NewText: "Submit"},
+		{Range: templRange(7, 2, 6), NewText: "Submit"},
+	}
+
+	t.Run("changes", func(t *testing.T) {
+		edit := &lsp.WorkspaceEdit{
+			Changes: map[lsp.DocumentURI][]lsp.TextEdit{
+				"file:///app/button_templ.go": goEdits,
+				"file:///app/main.go":         {mainGoEdit},
+			},
+		}
+		if err := p.convertWorkspaceEdit(edit); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := map[lsp.DocumentURI][]lsp.TextEdit{
+			"file:///app/button.templ": expectedTemplEdits,
+			"file:///app/main.go":      {mainGoEdit},
+		}
+		if diff := cmp.Diff(expected, edit.Changes); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("document changes", func(t *testing.T) {
+		version := int32(3)
+		edit := &lsp.WorkspaceEdit{
+			DocumentChanges: []lsp.TextDocumentEdit{
+				{
+					TextDocument: lsp.OptionalVersionedTextDocumentIdentifier{
+						TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "file:///app/button_templ.go"},
+						Version:                &version,
+					},
+					Edits: goEdits,
+				},
+				{
+					TextDocument: lsp.OptionalVersionedTextDocumentIdentifier{
+						TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "file:///app/main.go"},
+						Version:                &version,
+					},
+					Edits: []lsp.TextEdit{mainGoEdit},
+				},
+			},
+		}
+		if err := p.convertWorkspaceEdit(edit); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := []lsp.TextDocumentEdit{
+			{
+				TextDocument: lsp.OptionalVersionedTextDocumentIdentifier{
+					TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "file:///app/button.templ"},
+				},
+				Edits: expectedTemplEdits,
+			},
+			{
+				TextDocument: lsp.OptionalVersionedTextDocumentIdentifier{
+					TextDocumentIdentifier: lsp.TextDocumentIdentifier{URI: "file:///app/main.go"},
+					Version:                &version,
+				},
+				Edits: []lsp.TextEdit{mainGoEdit},
+			},
+		}
+		if diff := cmp.Diff(expected, edit.DocumentChanges); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("templ files that have not been loaded are read from disk", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "button.templ"), []byte(templContents), 0660); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		goURI := uri.File(filepath.Join(dir, "button_templ.go"))
+		edit := &lsp.WorkspaceEdit{
+			Changes: map[lsp.DocumentURI][]lsp.TextEdit{goURI: goEdits},
+		}
+		if err := p.convertWorkspaceEdit(edit); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := map[lsp.DocumentURI][]lsp.TextEdit{
+			uri.File(filepath.Join(dir, "button.templ")): expectedTemplEdits,
+		}
+		if diff := cmp.Diff(expected, edit.Changes); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("edits to templ files that can't be read are an error", func(t *testing.T) {
+		goURI := uri.File(filepath.Join(t.TempDir(), "missing_templ.go"))
+		edit := &lsp.WorkspaceEdit{
+			Changes: map[lsp.DocumentURI][]lsp.TextEdit{goURI: goEdits},
+		}
+		if err := p.convertWorkspaceEdit(edit); err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+}
+
+type testGopls struct {
+	lsp.Server
+	opened  []lsp.DocumentURI
+	closed  []lsp.DocumentURI
+	symbols []lsp.SymbolInformation
+}
+
+func (s *testGopls) DidClose(ctx context.Context, params *lsp.DidCloseTextDocumentParams) error {
+	s.closed = append(s.closed, params.TextDocument.URI)
+	return nil
+}
+
+func (s *testGopls) Symbols(ctx context.Context, params *lsp.WorkspaceSymbolParams) ([]lsp.SymbolInformation, error) {
+	return s.symbols, nil
+}
+
+func (s *testGopls) DidChangeWorkspaceFolders(ctx context.Context, params *lsp.DidChangeWorkspaceFoldersParams) error {
+	return nil
+}
+
+func (s *testGopls) DidOpen(ctx context.Context, params *lsp.DidOpenTextDocumentParams) error {
+	s.opened = append(s.opened, params.TextDocument.URI)
+	return nil
+}
+
+type testClient struct {
+	lsp.Client
+}
+
+func (c testClient) PublishDiagnostics(ctx context.Context, params *lsp.PublishDiagnosticsParams) error {
+	return nil
+}
+
+func TestDidChangeWorkspaceFolders(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.templ"), []byte("package main\n\ntempl A() {\n\t<div>A</div>\n}\n"), 0660); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.templ"), []byte("package main\n\ntempl B() {\n\t<div>B</div>\n}\n"), 0660); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	gopls := &testGopls{}
+	p := NewServer(log, gopls, NewSourceMapCache(), NewDiagnosticCache(), false, format.Config{})
+
+	// b.templ is already open in the editor, with unsaved changes.
+	bURI := string(uri.File(filepath.Join(dir, "b.templ")))
+	p.TemplSource.Set(bURI, NewDocument(log, "unsaved"))
+
+	ctx := lsp.WithClient(context.Background(), testClient{})
+	err := p.DidChangeWorkspaceFolders(ctx, &lsp.DidChangeWorkspaceFoldersParams{
+		Event: lsp.WorkspaceFoldersChangeEvent{
+			Added: []lsp.WorkspaceFolder{{URI: string(uri.File(dir)), Name: "added"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	aURI := string(uri.File(filepath.Join(dir, "a.templ")))
+	if _, ok := p.SourceMapCache.Get(aURI); !ok {
+		t.Errorf("expected source map for %q to be cached", aURI)
+	}
+	expected := []lsp.DocumentURI{uri.File(filepath.Join(dir, "a_templ.go"))}
+	if diff := cmp.Diff(expected, gopls.opened); diff != "" {
+		t.Error(diff)
+	}
+	if d, _ := p.TemplSource.Get(bURI); d.String() != "unsaved" {
+		t.Errorf("expected open document to be unchanged, got %q", d.String())
+	}
+
+	err = p.DidChangeWorkspaceFolders(ctx, &lsp.DidChangeWorkspaceFoldersParams{
+		Event: lsp.WorkspaceFoldersChangeEvent{
+			Removed: []lsp.WorkspaceFolder{{URI: string(uri.File(dir)), Name: "added"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := p.TemplSource.Get(aURI); ok {
+		t.Errorf("expected %q to be closed", aURI)
+	}
+	if _, ok := p.SourceMapCache.Get(aURI); ok {
+		t.Errorf("expected source map for %q to be removed", aURI)
+	}
+	if diff := cmp.Diff(expected, gopls.closed); diff != "" {
+		t.Error(diff)
+	}
+	if _, ok := p.TemplSource.Get(bURI); !ok {
+		t.Errorf("expected document opened by the editor to stay open")
+	}
+}
+
+func TestOrganizeImportsCodeAction(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		only     []lsp.CodeActionKind
+		expected []lsp.TextEdit
+	}{
+		{
+			name:  "source actions are not computed unless requested",
+			input: "package main\n\nimport (\n\t\"strings\"\n\t\"fmt\"\n)\n\ntempl A() {\n\t{ fmt.Sprint(1) }\n}\n",
+			only:  nil,
+		},
+		{
+			name:  "unused imports are removed without formatting the rest of the file",
+			input: "package main\n\nimport (\n\t\"strings\"\n\t\"fmt\"\n)\n\ntempl A() {\n<div>{ fmt.Sprint(1) }</div>\n}\n",
+			only:  []lsp.CodeActionKind{lsp.SourceOrganizeImports},
+			expected: []lsp.TextEdit{
+				{
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 2, Character: 0},
+						End:   lsp.Position{Line: 5, Character: 1},
+					},
+					NewText: "import \"fmt\"",
+				},
+			},
+		},
+		{
+			name:  "the source kind includes organize imports",
+			input: "package main\n\ntempl A() {\n<div>{ fmt.Sprint(1) }</div>\n}\n",
+			only:  []lsp.CodeActionKind{lsp.Source},
+			expected: []lsp.TextEdit{
+				{
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 0, Character: 12},
+						End:   lsp.Position{Line: 0, Character: 12},
+					},
+					NewText: "\n\nimport \"fmt\"",
+				},
+			},
+		},
+		{
+			name:  "all imports are removed",
+			input: "package main\n\nimport \"fmt\"\n\ntempl A() {\n<div>A</div>\n}\n",
+			only:  []lsp.CodeActionKind{lsp.SourceOrganizeImports},
+			expected: []lsp.TextEdit{
+				{
+					Range: lsp.Range{
+						Start: lsp.Position{Line: 2, Character: 0},
+						End:   lsp.Position{Line: 4, Character: 0},
+					},
+				},
+			},
+		},
+		{
+			name:  "no action is returned when the imports are organized",
+			input: "package main\n\nimport \"fmt\"\n\ntempl A() {\n<div>{ fmt.Sprint(1) }</div>\n}\n",
+			only:  []lsp.CodeActionKind{lsp.SourceOrganizeImports},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+			p := NewServer(log, &testGopls{}, NewSourceMapCache(), NewDiagnosticCache(), false, format.Config{})
+			templURI := uri.File(filepath.Join(t.TempDir(), "a.templ"))
+			p.TemplSource.Set(string(templURI), NewDocument(log, tt.input))
+
+			actions := p.organizeImportsCodeAction(templURI, tt.only)
+			if tt.expected == nil {
+				if len(actions) != 0 {
+					t.Fatalf("expected no actions, got %v", actions)
+				}
+				return
+			}
+			if len(actions) != 1 {
+				t.Fatalf("expected 1 action, got %d", len(actions))
+			}
+			if diff := cmp.Diff(tt.expected, actions[0].Edit.Changes[templURI]); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestSymbols(t *testing.T) {
+	templContents := "package main\n\ntempl Button() {\n\t<button>ok</button>\n}\n"
+	tf, err := parser.ParseString(templContents)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+	var goCode strings.Builder
+	output, err := generator.Generate(tf, &goCode)
+	if err != nil {
+		t.Fatalf("failed to generate Go code: %v", err)
+	}
+	var buttonRange lsp.Range
+	for i, line := range strings.Split(goCode.String(), "\n") {
+		if col := strings.Index(line, "func Button()"); col >= 0 {
+			col += len("func ")
+			buttonRange = lsp.Range{
+				Start: lsp.Position{Line: uint32(i), Character: uint32(col)},
+				End:   lsp.Position{Line: uint32(i), Character: uint32(col + len("Button"))},
+			}
+		}
+	}
+	mainRange := lsp.Range{Start: lsp.Position{Line: 4, Character: 5}, End: lsp.Position{Line: 4, Character: 9}}
+	// A templ file that hasn't been loaded, for example because preloading is disabled.
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "unloaded.templ"), []byte(templContents), 0660); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	gopls := &testGopls{
+		symbols: []lsp.SymbolInformation{
+			{Name: "Button", Location: lsp.Location{URI: "file:///app/button_templ.go", Range: buttonRange}},
+			{Name: "Button", Location: lsp.Location{URI: uri.File(filepath.Join(dir, "unloaded_templ.go")), Range: buttonRange}},
+			{Name: "Missing", Location: lsp.Location{URI: "file:///app/missing_templ.go", Range: buttonRange}},
+			{Name: "main", Location: lsp.Location{URI: "file:///app/main.go", Range: mainRange}},
+		},
+	}
+	p := NewServer(log, gopls, NewSourceMapCache(), NewDiagnosticCache(), false, format.Config{})
+	p.SourceMapCache.Set("file:///app/button.templ", output.SourceMap)
+
+	actual, err := p.Symbols(context.Background(), &lsp.WorkspaceSymbolParams{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	templButtonRange := lsp.Range{
+		Start: lsp.Position{Line: 2, Character: 6},
+		End:   lsp.Position{Line: 2, Character: 12},
+	}
+	expected := []lsp.SymbolInformation{
+		{Name: "Button", Location: lsp.Location{URI: "file:///app/button.templ", Range: templButtonRange}},
+		{Name: "Button", Location: lsp.Location{URI: uri.File(filepath.Join(dir, "unloaded.templ")), Range: templButtonRange}},
+		{Name: "main", Location: lsp.Location{URI: "file:///app/main.go", Range: mainRange}},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestDidChangeWorkspaceFoldersNested(t *testing.T) {
+	dir := t.TempDir()
+	inner := filepath.Join(dir, "inner")
+	if err := os.MkdirAll(inner, 0770); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.templ"), []byte("package main\n\ntempl A() {\n\t<div>A</div>\n}\n"), 0660); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(inner, "b.templ"), []byte("package inner\n\ntempl B() {\n\t<div>B</div>\n}\n"), 0660); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	gopls := &testGopls{}
+	p := NewServer(log, gopls, NewSourceMapCache(), NewDiagnosticCache(), false, format.Config{})
+
+	ctx := lsp.WithClient(context.Background(), testClient{})
+	outerFolder := lsp.WorkspaceFolder{URI: string(uri.File(dir)), Name: "outer"}
+	innerFolder := lsp.WorkspaceFolder{URI: string(uri.File(inner)), Name: "inner"}
+	changeFolders := func(event lsp.WorkspaceFoldersChangeEvent) {
+		t.Helper()
+		if err := p.DidChangeWorkspaceFolders(ctx, &lsp.DidChangeWorkspaceFoldersParams{Event: event}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	changeFolders(lsp.WorkspaceFoldersChangeEvent{Added: []lsp.WorkspaceFolder{outerFolder, innerFolder}})
+
+	aURI := string(uri.File(filepath.Join(dir, "a.templ")))
+	bURI := string(uri.File(filepath.Join(inner, "b.templ")))
+
+	// The inner folder's documents are still within the outer folder.
+	changeFolders(lsp.WorkspaceFoldersChangeEvent{Removed: []lsp.WorkspaceFolder{innerFolder}})
+	if len(gopls.closed) != 0 {
+		t.Errorf("expected no documents to be closed, got %v", gopls.closed)
+	}
+	for _, u := range []string{aURI, bURI} {
+		if _, ok := p.TemplSource.Get(u); !ok {
+			t.Errorf("expected %q to stay open", u)
+		}
+	}
+
+	// Documents in the inner folder stay open when the outer folder is removed.
+	changeFolders(lsp.WorkspaceFoldersChangeEvent{Added: []lsp.WorkspaceFolder{innerFolder}})
+	changeFolders(lsp.WorkspaceFoldersChangeEvent{Removed: []lsp.WorkspaceFolder{outerFolder}})
+	expected := []lsp.DocumentURI{uri.File(filepath.Join(dir, "a_templ.go"))}
+	if diff := cmp.Diff(expected, gopls.closed); diff != "" {
+		t.Error(diff)
+	}
+	if _, ok := p.TemplSource.Get(bURI); !ok {
+		t.Errorf("expected %q to stay open", bURI)
+	}
+
+	changeFolders(lsp.WorkspaceFoldersChangeEvent{Removed: []lsp.WorkspaceFolder{innerFolder}})
+	expected = append(expected, uri.File(filepath.Join(inner, "b_templ.go")))
+	if diff := cmp.Diff(expected, gopls.closed); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -8,13 +8,17 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"syscall"
 
 	"github.com/a-h/templ"
 	"github.com/a-h/templ/cmd/templ/fmtcmd"
 	"github.com/a-h/templ/cmd/templ/generatecmd"
 	"github.com/a-h/templ/cmd/templ/infocmd"
+	"github.com/a-h/templ/cmd/templ/listcmd"
 	"github.com/a-h/templ/cmd/templ/lspcmd"
+	"github.com/a-h/templ/cmd/templ/ownerscmd"
+	"github.com/a-h/templ/cmd/templ/refactorcmd"
 	"github.com/a-h/templ/cmd/templ/sloghandler"
 	"github.com/a-h/templ/internal/format"
 	"github.com/fatih/color"
@@ -38,6 +42,9 @@ commands:
   fmt        Formats templ files
   lsp        Starts a language server for templ files
   info       Displays information about the templ environment
+  list       Lists templ components and their metadata
+  owners     Reports component ownership
+  refactor   Refactors templ files
   version    Prints the version
 `
 
@@ -49,6 +56,12 @@ func run(stdin io.Reader, stdout, stderr io.Writer, args []string) (code int) {
 	switch args[1] {
 	case "info":
 		return infoCmd(stdout, stderr, args[2:])
+	case "list":
+		return listCmd(stdout, stderr, args[2:])
+	case "owners":
+		return ownersCmd(stdout, stderr, args[2:])
+	case "refactor":
+		return refactorCmd(stdout, stderr, args[2:])
 	case "generate":
 		return generateCmd(stdout, stderr, args[2:])
 	case "fmt":
@@ -73,6 +86,10 @@ Displays information about the templ environment.
 Args:
   -json
     Output information in JSON format to stdout. (default false)
+  -components
+    Include the components declared in templ files. (default false)
+  -path <path>
+    Path to search for templ files when -components is set. (default ".")
   -v
     Set log verbosity level to "debug". (default "info")
   -log-level
@@ -84,6 +101,8 @@ Args:
 func infoCmd(stdout, stderr io.Writer, args []string) (code int) {
 	cmd := flag.NewFlagSet("diagnose", flag.ExitOnError)
 	jsonFlag := cmd.Bool("json", false, "")
+	componentsFlag := cmd.Bool("components", false, "")
+	pathFlag := cmd.String("path", ".", "")
 	verboseFlag := cmd.Bool("v", false, "")
 	logLevelFlag := cmd.String("log-level", "info", "")
 	helpFlag := cmd.Bool("help", false, "")
@@ -109,6 +128,66 @@ func infoCmd(stdout, stderr io.Writer, args []string) (code int) {
 	}()
 
 	err = infocmd.Run(ctx, log, stdout, infocmd.Arguments{
+		JSON:       *jsonFlag,
+		Components: *componentsFlag,
+		Path:       *pathFlag,
+	})
+	if err != nil {
+		_, _ = color.New(color.FgRed).Fprint(stderr, "(✗) ")
+		_, _ = fmt.Fprintln(stderr, "Command failed: "+err.Error())
+		return 1
+	}
+	return 0
+}
+
+const listUsageText = `usage: templ list [<args>...]
+
+Lists the templ components in a directory tree, including any metadata
+added with //templ:meta key=value annotations.
+
+Args:
+  -path <path>
+    Path to search for templ files. (default ".")
+  -json
+    Output components in JSON format to stdout. (default false)
+  -v
+    Set log verbosity level to "debug". (default "info")
+  -log-level
+    Set log verbosity level. (default "info", options: "debug", "info", "warn", "error")
+  -help
+    Print help and exit.
+`
+
+func listCmd(stdout, stderr io.Writer, args []string) (code int) {
+	cmd := flag.NewFlagSet("list", flag.ExitOnError)
+	pathFlag := cmd.String("path", ".", "")
+	jsonFlag := cmd.Bool("json", false, "")
+	verboseFlag := cmd.Bool("v", false, "")
+	logLevelFlag := cmd.String("log-level", "info", "")
+	helpFlag := cmd.Bool("help", false, "")
+	err := cmd.Parse(args)
+	if err != nil {
+		_, _ = fmt.Fprint(stderr, listUsageText)
+		return 64 // EX_USAGE
+	}
+	if *helpFlag {
+		_, _ = fmt.Fprint(stdout, listUsageText)
+		return
+	}
+
+	log := sloghandler.NewLogger(*logLevelFlag, *verboseFlag, stderr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt)
+	go func() {
+		<-signalChan
+		_, _ = fmt.Fprintln(stderr, "Stopping...")
+		cancel()
+	}()
+
+	err = listcmd.Run(ctx, log, stdout, listcmd.Arguments{
+		Path: *pathFlag,
 		JSON: *jsonFlag,
 	})
 	if err != nil {
@@ -119,6 +198,154 @@ func infoCmd(stdout, stderr io.Writer, args []string) (code int) {
 	return 0
 }
 
+const ownersUsageText = `usage: templ owners report [<args>...]
+
+Reports the templ components owned by each team.
+
+The owner of a component is taken from its //templ:meta owner=<team>
+annotation. Components without an annotation are assigned owners using the
+CODEOWNERS file in the .github, root or docs directory of the repository
+that contains the path.
+
+Args:
+  -path <path>
+    Path to search for templ files. (default ".")
+  -json
+    Output the report in JSON format to stdout. (default false)
+  -v
+    Set log verbosity level to "debug". (default "info")
+  -log-level
+    Set log verbosity level. (default "info", options: "debug", "info", "warn", "error")
+  -help
+    Print help and exit.
+`
+
+func ownersCmd(stdout, stderr io.Writer, args []string) (code int) {
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-help" || args[0] == "--help" || args[0] == "-h") {
+		_, _ = fmt.Fprint(stdout, ownersUsageText)
+		return 0
+	}
+	if len(args) == 0 || args[0] != "report" {
+		_, _ = fmt.Fprint(stderr, ownersUsageText)
+		return 64 // EX_USAGE
+	}
+	cmd := flag.NewFlagSet("owners report", flag.ExitOnError)
+	pathFlag := cmd.String("path", ".", "")
+	jsonFlag := cmd.Bool("json", false, "")
+	verboseFlag := cmd.Bool("v", false, "")
+	logLevelFlag := cmd.String("log-level", "info", "")
+	helpFlag := cmd.Bool("help", false, "")
+	err := cmd.Parse(args[1:])
+	if err != nil {
+		_, _ = fmt.Fprint(stderr, ownersUsageText)
+		return 64 // EX_USAGE
+	}
+	if *helpFlag {
+		_, _ = fmt.Fprint(stdout, ownersUsageText)
+		return
+	}
+
+	log := sloghandler.NewLogger(*logLevelFlag, *verboseFlag, stderr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt)
+	go func() {
+		<-signalChan
+		_, _ = fmt.Fprintln(stderr, "Stopping...")
+		cancel()
+	}()
+
+	err = ownerscmd.Run(ctx, log, stdout, ownerscmd.Arguments{
+		Path: *pathFlag,
+		JSON: *jsonFlag,
+	})
+	if err != nil {
+		_, _ = color.New(color.FgRed).Fprint(stderr, "(✗) ")
+		_, _ = fmt.Fprintln(stderr, "Command failed: "+err.Error())
+		return 1
+	}
+	return 0
+}
+
+const refactorUsageText = `usage: templ refactor split [<args>...] <file>
+
+Moves components from a templ file into a new file in the same package.
+
+Imports are copied to the new file, and unused imports are removed from both
+files. Doc comments directly above a component are moved with it, and the file
+header, such as //go:build constraints, is copied to the new file.
+
+Both files are written formatted, as with templ fmt, so any unformatted code in
+the source file is also reformatted.
+
+Args:
+  -to <file>
+    The templ file to create. It must not already exist.
+  -components <names>
+    Comma separated names of the components to move, e.g. Header,Footer
+    Methods are named Type.Method, e.g. Button.Render. The method name alone
+    can be used if only one type has that method, and there's no component
+    with that name.
+  -v
+    Set log verbosity level to "debug". (default "info")
+  -log-level
+    Set log verbosity level. (default "info", options: "debug", "info", "warn", "error")
+  -help
+    Print help and exit.
+`
+
+func refactorCmd(stdout, stderr io.Writer, args []string) (code int) {
+	if len(args) > 0 && (args[0] == "help" || args[0] == "-help" || args[0] == "--help" || args[0] == "-h") {
+		_, _ = fmt.Fprint(stdout, refactorUsageText)
+		return 0
+	}
+	if len(args) == 0 || args[0] != "split" {
+		_, _ = fmt.Fprint(stderr, refactorUsageText)
+		return 64 // EX_USAGE
+	}
+	cmd := flag.NewFlagSet("refactor split", flag.ExitOnError)
+	toFlag := cmd.String("to", "", "")
+	componentsFlag := cmd.String("components", "", "")
+	verboseFlag := cmd.Bool("v", false, "")
+	logLevelFlag := cmd.String("log-level", "info", "")
+	helpFlag := cmd.Bool("help", false, "")
+	err := cmd.Parse(args[1:])
+	if err != nil {
+		_, _ = fmt.Fprint(stderr, refactorUsageText)
+		return 64 // EX_USAGE
+	}
+	if *helpFlag {
+		_, _ = fmt.Fprint(stdout, refactorUsageText)
+		return
+	}
+	if cmd.NArg() != 1 || *toFlag == "" || *componentsFlag == "" {
+		_, _ = fmt.Fprint(stderr, refactorUsageText)
+		return 64 // EX_USAGE
+	}
+
+	log := sloghandler.NewLogger(*logLevelFlag, *verboseFlag, stderr)
+
+	var components []string
+	for name := range strings.SplitSeq(*componentsFlag, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			components = append(components, name)
+		}
+	}
+	moved, err := refactorcmd.Split(log, refactorcmd.SplitArguments{
+		FileName:   cmd.Arg(0),
+		To:         *toFlag,
+		Components: components,
+	})
+	if err != nil {
+		_, _ = color.New(color.FgRed).Fprint(stderr, "(✗) ")
+		_, _ = fmt.Fprintln(stderr, "Command failed: "+err.Error())
+		return 1
+	}
+	_, _ = fmt.Fprintln(stdout, refactorcmd.Report(cmd.Arg(0), *toFlag, moved))
+	return 0
+}
+
 func generateCmd(stdout, stderr io.Writer, args []string) (code int) {
 	ctx, cancel := context.WithCancel(context.Background())
 	signalChan := make(chan os.Signal, 1)
@@ -65,6 +65,36 @@ func TestMain(t *testing.T) {
 			expectedStdout: infoUsageText,
 			expectedCode:   0,
 		},
+		{
+			name:           `"templ list --help" prints usage`,
+			args:           []string{"templ", "list", "--help"},
+			expectedStdout: listUsageText,
+			expectedCode:   0,
+		},
+		{
+			name:           `"templ owners report --help" prints usage`,
+			args:           []string{"templ", "owners", "report", "--help"},
+			expectedStdout: ownersUsageText,
+			expectedCode:   0,
+		},
+		{
+			name:           `"templ refactor split --help" prints usage`,
+			args:           []string{"templ", "refactor", "split", "--help"},
+			expectedStdout: refactorUsageText,
+			expectedCode:   0,
+		},
+		{
+			name:           `"templ refactor split" without a file prints usage to stderr`,
+			args:           []string{"templ", "refactor", "split", "-to", "new.templ"},
+			expectedStderr: refactorUsageText,
+			expectedCode:   64,
+		},
+		{
+			name:           `"templ owners" without a subcommand prints usage to stderr`,
+			args:           []string{"templ", "owners"},
+			expectedStderr: ownersUsageText,
+			expectedCode:   64,
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,118 @@
+package ownerscmd
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// codeOwnersLocations are the locations searched for a CODEOWNERS file,
+// relative to the root directory, in the order used by GitHub.
+var codeOwnersLocations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+}
+
+// CodeOwners maps file paths to owners using the rules in a CODEOWNERS file.
+type CodeOwners struct {
+	rules []codeOwnersRule
+}
+
+type codeOwnersRule struct {
+	pattern string
+	owners  []string
+}
+
+// LoadCodeOwners loads the first CODEOWNERS file found within the root
+// directory. If no file is found, an empty set of rules is returned.
+func LoadCodeOwners(root string) (co *CodeOwners, err error) {
+	for _, loc := range codeOwnersLocations {
+		f, err := os.Open(filepath.Join(root, filepath.FromSlash(loc)))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		defer func() { _ = f.Close() }()
+		return ParseCodeOwners(f)
+	}
+	return &CodeOwners{}, nil
+}
+
+// ParseCodeOwners parses a CODEOWNERS file.
+func ParseCodeOwners(r io.Reader) (co *CodeOwners, err error) {
+	co = &CodeOwners{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if before, _, ok := strings.Cut(line, "#"); ok {
+			line = before
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		co.rules = append(co.rules, codeOwnersRule{
+			pattern: fields[0],
+			owners:  fields[1:],
+		})
+	}
+	return co, scanner.Err()
+}
+
+// Owners returns the owners of the slash separated path, relative to the
+// root of the repository. As with GitHub, the last matching rule wins.
+func (co *CodeOwners) Owners(p string) (owners []string) {
+	for i := len(co.rules) - 1; i >= 0; i-- {
+		if matchCodeOwnersPattern(co.rules[i].pattern, p) {
+			return co.rules[i].owners
+		}
+	}
+	return nil
+}
+
+func matchCodeOwnersPattern(pattern, p string) bool {
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	pattern = strings.TrimSuffix(strings.TrimSuffix(pattern, "**"), "/")
+	if pattern == "" {
+		return true
+	}
+	// Patterns without a slash match at any depth, in the same way as .gitignore.
+	if !anchored && !strings.Contains(pattern, "/") {
+		for seg := range strings.SplitSeq(p, "/") {
+			if ok, _ := path.Match(pattern, seg); ok {
+				return true
+			}
+		}
+		return false
+	}
+	// Otherwise, the pattern matches the path, or any directory containing it.
+	// A leading "**/" allows the match to start at any depth.
+	segments := strings.Split(p, "/")
+	starts := 1
+	if rest, ok := strings.CutPrefix(pattern, "**/"); ok {
+		pattern, starts = rest, len(segments)
+	}
+	// As in GitHub, a "*" in the last segment only matches files in that directory,
+	// so "docs/*" matches "docs/a.md", but not "docs/a/b.md".
+	ends := 0
+	if strings.Contains(pattern[strings.LastIndex(pattern, "/")+1:], "*") {
+		ends = len(segments) - 1
+	}
+	for start := range starts {
+		for end := max(start, ends); end < len(segments); end++ {
+			if ok, _ := path.Match(pattern, strings.Join(segments[start:end+1], "/")); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
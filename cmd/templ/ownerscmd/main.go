@@ -0,0 +1,137 @@
+package ownerscmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/a-h/templ/cmd/templ/listcmd"
+)
+
+// Unowned is the owner reported for components without an owner.
+const Unowned = "(unowned)"
+
+type Arguments struct {
+	Path string `flag:"path" help:"Path to search for templ files."`
+	JSON bool   `flag:"json" help:"Output the report as JSON."`
+}
+
+// Team is a set of components with a common owner.
+type Team struct {
+	Owner      string              `json:"owner"`
+	Components []listcmd.Component `json:"components"`
+}
+
+func Run(ctx context.Context, log *slog.Logger, stdout io.Writer, args Arguments) (err error) {
+	teams, err := Report(ctx, log, args.Path)
+	if err != nil {
+		return err
+	}
+	if args.JSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(teams)
+	}
+	for i, team := range teams {
+		if i > 0 {
+			_, _ = fmt.Fprintln(stdout)
+		}
+		_, _ = fmt.Fprintf(stdout, "%s (%d)\n", team.Owner, len(team.Components))
+		for _, c := range team.Components {
+			_, _ = fmt.Fprintf(stdout, "  %s:%d %s\n", c.File, c.Line, c.Name)
+		}
+	}
+	return nil
+}
+
+// Report groups the components within path by owner. The owner of a
+// component is taken from its //templ:meta owner=<team> annotation, falling
+// back to the CODEOWNERS rules for its file. As in GitHub, CODEOWNERS is read
+// from the root of the repository, even if path is a subdirectory. Components
+// without either are reported under Unowned.
+func Report(ctx context.Context, log *slog.Logger, path string) (teams []Team, err error) {
+	if path == "" {
+		path = "."
+	}
+	components, err := listcmd.List(ctx, log, path)
+	if err != nil {
+		return nil, err
+	}
+	root, err := repositoryRoot(path)
+	if err != nil {
+		return nil, err
+	}
+	co, err := LoadCodeOwners(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CODEOWNERS: %w", err)
+	}
+	byOwner := map[string][]listcmd.Component{}
+	for _, c := range components {
+		for _, owner := range componentOwners(root, co, c) {
+			byOwner[owner] = append(byOwner[owner], c)
+		}
+	}
+	teams = []Team{}
+	for owner, components := range byOwner {
+		teams = append(teams, Team{Owner: owner, Components: components})
+	}
+	slices.SortFunc(teams, func(a, b Team) int {
+		// Unowned components are listed last.
+		if (a.Owner == Unowned) != (b.Owner == Unowned) {
+			if a.Owner == Unowned {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a.Owner, b.Owner)
+	})
+	return teams, nil
+}
+
+func componentOwners(root string, co *CodeOwners, c listcmd.Component) (owners []string) {
+	for o := range strings.SplitSeq(c.Meta["owner"], ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			owners = append(owners, o)
+		}
+	}
+	if len(owners) > 0 {
+		return owners
+	}
+	fileName, err := filepath.Abs(filepath.FromSlash(c.File))
+	if err != nil {
+		return []string{Unowned}
+	}
+	rel, err := filepath.Rel(root, fileName)
+	if err != nil {
+		return []string{Unowned}
+	}
+	if owners = co.Owners(filepath.ToSlash(rel)); len(owners) > 0 {
+		return owners
+	}
+	return []string{Unowned}
+}
+
+// repositoryRoot returns the directory containing .git, searching upwards from
+// path. If path isn't within a repository, the absolute path is returned.
+func repositoryRoot(path string) (root string, err error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	for dir := abs; ; {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return abs, nil
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,165 @@
+package ownerscmd
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCodeOwners(t *testing.T) {
+	co, err := ParseCodeOwners(strings.NewReader(`# Default owners.
+*                 @org/web
+
+*.templ           @org/frontend
+/components/      @org/design-system # Shared components.
+docs/**           @org/docs
+**/admin/*.templ  @org/admin
+/components/legacy/
+content/*         @org/content
+`))
+	if err != nil {
+		t.Fatalf("failed to parse CODEOWNERS: %v", err)
+	}
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{path: "main.go", expected: []string{"@org/web"}},
+		{path: "pages/home.templ", expected: []string{"@org/frontend"}},
+		{path: "components/button.templ", expected: []string{"@org/design-system"}},
+		{path: "components/forms/input.templ", expected: []string{"@org/design-system"}},
+		{path: "pages/components/card.templ", expected: []string{"@org/frontend"}},
+		{path: "docs/guide/index.templ", expected: []string{"@org/docs"}},
+		{path: "pages/admin/users.templ", expected: []string{"@org/admin"}},
+		{path: "components/legacy/old.templ", expected: []string{}},
+		{path: "content/index.templ", expected: []string{"@org/content"}},
+		{path: "content/blog/post.templ", expected: []string{"@org/frontend"}},
+		{path: "content/blog/feed.xml", expected: []string{"@org/web"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			actual := co.Owners(tt.path)
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".github/CODEOWNERS": "/components/ @org/design-system\n",
+		"components/button.templ": `package components
+
+templ Button() {
+	<button></button>
+}
+
+//templ:meta owner=@org/checkout,@org/design-system
+templ PayButton() {
+	<button></button>
+}
+`,
+		"pages/home.templ": `package pages
+
+//templ:meta owner=@org/marketing
+templ Home() {
+	<div></div>
+}
+
+templ About() {
+	<div></div>
+}
+`,
+	}
+	for name, contents := range files {
+		fileName := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(fileName, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	teams, err := Report(context.Background(), log, dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual := map[string][]string{}
+	var order []string
+	for _, team := range teams {
+		order = append(order, team.Owner)
+		for _, c := range team.Components {
+			actual[team.Owner] = append(actual[team.Owner], c.Name)
+		}
+	}
+	expected := map[string][]string{
+		"@org/checkout":      {"PayButton"},
+		"@org/design-system": {"Button", "PayButton"},
+		"@org/marketing":     {"Home"},
+		Unowned:              {"About"},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+	expectedOrder := []string{"@org/checkout", "@org/design-system", "@org/marketing", Unowned}
+	if diff := cmp.Diff(expectedOrder, order); diff != "" {
+		t.Errorf("unexpected team order:\n%s", diff)
+	}
+}
+
+func TestReportSubdirectory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".git/HEAD":          "ref: refs/heads/main\n",
+		".github/CODEOWNERS": "/web/ @org/web\n",
+		"web/components/button.templ": `package components
+
+templ Button() {
+	<button></button>
+}
+`,
+	}
+	for name, contents := range files {
+		fileName := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fileName), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(fileName, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, path := range []string{dir, filepath.Join(dir, "web"), filepath.Join(dir, "web", "components")} {
+		t.Run(path, func(t *testing.T) {
+			teams, err := Report(context.Background(), log, path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(teams) != 1 || teams[0].Owner != "@org/web" {
+				t.Errorf("expected the components to be owned by @org/web, got %v", teams)
+			}
+		})
+	}
+	t.Run("working directory", func(t *testing.T) {
+		t.Chdir(filepath.Join(dir, "web"))
+		teams, err := Report(context.Background(), log, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(teams) != 1 || teams[0].Owner != "@org/web" {
+			t.Errorf("expected the components to be owned by @org/web, got %v", teams)
+		}
+	})
+}
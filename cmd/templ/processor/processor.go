@@ -29,10 +29,23 @@ func Process(dir string, f func(fileName string) (error, bool), workerCount int,
 }
 
 func FindTemplates(srcPath string, shouldSkip func(string) bool, output chan<- string) (err error) {
-	return filepath.WalkDir(srcPath, func(currentPath string, info fs.DirEntry, err error) error {
+	// filepath.WalkDir doesn't follow symlinks, so if the root is a symlink,
+	// walk its target, but report paths relative to srcPath.
+	walkPath := srcPath
+	if resolved, err := filepath.EvalSymlinks(srcPath); err == nil {
+		walkPath = resolved
+	}
+	return filepath.WalkDir(walkPath, func(currentPath string, info fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if walkPath != srcPath {
+			rel, err := filepath.Rel(walkPath, currentPath)
+			if err != nil {
+				return err
+			}
+			currentPath = filepath.Join(srcPath, rel)
+		}
 		if info.IsDir() && skipdir.ShouldSkip(currentPath) {
 			return filepath.SkipDir
 		}
@@ -2,6 +2,8 @@ package processor
 
 import (
 	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -16,4 +18,39 @@ func TestFindTemplates(t *testing.T) {
 			t.Fatalf("expected os.IsNotExist(err) to be true, but got: %v", err)
 		}
 	})
+	t.Run("follows a symlinked root directory", func(t *testing.T) {
+		dir := t.TempDir()
+		target := filepath.Join(dir, "target")
+		if err := os.MkdirAll(filepath.Join(target, "sub"), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		for _, name := range []string{"a.templ", "sub/b.templ", "c.go"} {
+			if err := os.WriteFile(filepath.Join(target, name), nil, 0644); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+		}
+		link := filepath.Join(dir, "link")
+		if err := os.Symlink(target, link); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+
+		output := make(chan string)
+		errs := make(chan error, 1)
+		go func() {
+			defer close(output)
+			errs <- FindTemplates(link, nil, output)
+		}()
+		var actual []string
+		for fileName := range output {
+			actual = append(actual, fileName)
+		}
+		if err := <-errs; err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		slices.Sort(actual)
+		expected := []string{filepath.Join(link, "a.templ"), filepath.Join(link, "sub", "b.templ")}
+		if !slices.Equal(expected, actual) {
+			t.Errorf("expected %v, got %v", expected, actual)
+		}
+	})
 }
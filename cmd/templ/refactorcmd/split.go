@@ -0,0 +1,283 @@
+package refactorcmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/natefinch/atomic"
+
+	"github.com/a-h/templ/internal/imports"
+	"github.com/a-h/templ/parser/v2"
+)
+
+type SplitArguments struct {
+	// FileName is the templ file to move components from.
+	FileName string
+	// To is the templ file to create.
+	To string
+	// Components are the names of the components to move.
+	Components []string
+}
+
+// Split moves components from one templ file into a new file in the same
+// package. Imports are copied to the new file, and unused imports are removed
+// from both files. It returns the names of the moved components.
+func Split(log *slog.Logger, args SplitArguments) (moved []string, err error) {
+	if len(args.Components) == 0 {
+		return nil, errors.New("no components to move")
+	}
+	if _, err = os.Stat(args.To); err == nil {
+		return nil, fmt.Errorf("%s already exists", args.To)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	src, err := parser.Parse(args.FileName)
+	if err != nil {
+		return nil, fmt.Errorf("%s parsing error: %w", args.FileName, err)
+	}
+	src.Filepath = args.FileName
+
+	dst := &parser.TemplateFile{
+		Header:   copyHeader(src.Header),
+		Package:  src.Package,
+		Filepath: args.To,
+	}
+	importsNode, err := copyImports(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", args.FileName, err)
+	}
+	if importsNode != nil {
+		dst.Nodes = append(dst.Nodes, importsNode)
+	}
+
+	selected, err := selectNodes(src.Nodes, args.Components)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", args.FileName, err)
+	}
+
+	var kept []parser.TemplateFileNode
+	for i, node := range src.Nodes {
+		if !selected[i] {
+			kept = append(kept, node)
+			continue
+		}
+		// Move the doc comment directly above the component along with it.
+		if len(kept) > 0 {
+			if prev, ok := kept[len(kept)-1].(*parser.TemplateFileGoExpression); ok && isDirectlyAbove(prev, node) {
+				var comment string
+				prev.Expression.Value, comment = cutTrailingComment(prev.Expression.Value)
+				if comment != "" {
+					dst.Nodes = append(dst.Nodes, &parser.TemplateFileGoExpression{
+						Expression: parser.Expression{Value: comment},
+					})
+				}
+				if strings.TrimSpace(prev.Expression.Value) == "" {
+					kept = kept[:len(kept)-1]
+				}
+			}
+		}
+		dst.Nodes = append(dst.Nodes, node)
+		moved = append(moved, nodeName(node))
+	}
+	src.Nodes = kept
+
+	srcContents, err := format(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", args.FileName, err)
+	}
+	dstContents, err := format(dst)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", args.To, err)
+	}
+	// Write the new file first, so that components are never lost.
+	if err = atomic.WriteFile(args.To, bytes.NewReader(dstContents)); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", args.To, err)
+	}
+	if err = atomic.WriteFile(args.FileName, bytes.NewReader(srcContents)); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", args.FileName, err)
+	}
+	log.Debug("Split file", slog.String("from", args.FileName), slog.String("to", args.To), slog.Any("components", moved))
+	return moved, nil
+}
+
+// selectNodes returns the indexes of the nodes that match the component names.
+// Each name must match exactly one node. Methods can be selected with
+// Type.Method, or by the method name alone if only one type has the method.
+func selectNodes(nodes []parser.TemplateFileNode, components []string) (selected map[int]bool, err error) {
+	selected = make(map[int]bool)
+	for _, component := range components {
+		// Prefer the component with the exact name, so that a top-level component can be
+		// selected even if there are methods with the same name.
+		matches := matchNodes(nodes, func(name string) bool { return name == component })
+		if len(matches) == 0 {
+			matches = matchNodes(nodes, func(name string) bool { return strings.HasSuffix(name, "."+component) })
+		}
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("component %q not found", component)
+		case 1:
+			selected[matches[0]] = true
+			continue
+		}
+		names := make([]string, len(matches))
+		for i, m := range matches {
+			names[i] = nodeName(nodes[m])
+		}
+		return nil, fmt.Errorf("component %q is ambiguous, use one of %s", component, strings.Join(names, ", "))
+	}
+	return selected, nil
+}
+
+// matchNodes returns the indexes of the named nodes that match.
+func matchNodes(nodes []parser.TemplateFileNode, match func(name string) bool) (matches []int) {
+	for i, node := range nodes {
+		if name := nodeName(node); name != "" && match(name) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// copyHeader copies the comments and build constraints at the top of the file.
+func copyHeader(header []*parser.TemplateFileGoExpression) (copied []*parser.TemplateFileGoExpression) {
+	for _, h := range header {
+		c := *h
+		copied = append(copied, &c)
+	}
+	return copied
+}
+
+func format(tf *parser.TemplateFile) (contents []byte, err error) {
+	tf, err = imports.Process(tf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process imports: %w", err)
+	}
+	var buf bytes.Buffer
+	if err = tf.Write(&buf); err != nil {
+		return nil, fmt.Errorf("formatting error: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// copyImports returns a Go expression containing the imports from the file,
+// without any other declarations.
+func copyImports(tf *parser.TemplateFile) (node *parser.TemplateFileGoExpression, err error) {
+	var specs []*ast.ImportSpec
+	for _, n := range tf.Nodes {
+		goExpr, ok := n.(*parser.TemplateFileGoExpression)
+		if !ok {
+			continue
+		}
+		f, err := goparser.ParseFile(token.NewFileSet(), "", "package p\n"+goExpr.Expression.Value, goparser.ImportsOnly)
+		if err != nil {
+			// Not every Go expression is a complete declaration.
+			continue
+		}
+		specs = append(specs, f.Imports...)
+	}
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	var sb strings.Builder
+	sb.WriteString("import (\n")
+	for _, spec := range specs {
+		sb.WriteString("\t")
+		if spec.Name != nil {
+			sb.WriteString(spec.Name.Name + " ")
+		}
+		sb.WriteString(spec.Path.Value + "\n")
+	}
+	sb.WriteString(")")
+	return &parser.TemplateFileGoExpression{
+		Expression: parser.Expression{Value: sb.String()},
+	}, nil
+}
+
+// cutTrailingComment splits the comment lines at the end of a Go expression
+// from the rest of the code.
+func cutTrailingComment(s string) (code, comment string) {
+	lines := strings.Split(s, "\n")
+	i := len(lines)
+	for i > 0 && strings.HasPrefix(strings.TrimSpace(lines[i-1]), "//") {
+		i--
+	}
+	if i == len(lines) {
+		return s, ""
+	}
+	return strings.TrimRight(strings.Join(lines[:i], "\n"), "\n"), strings.Join(lines[i:], "\n")
+}
+
+func isDirectlyAbove(prev *parser.TemplateFileGoExpression, node parser.TemplateFileNode) bool {
+	lastLine := prev.Expression.Range.From.Line + uint32(strings.Count(prev.Expression.Value, "\n"))
+	return lastLine+1 == nodeRange(node).From.Line
+}
+
+func nodeRange(node parser.TemplateFileNode) parser.Range {
+	switch n := node.(type) {
+	case *parser.HTMLTemplate:
+		return n.Range
+	case *parser.CSSTemplate:
+		return n.Range
+	case *parser.ScriptTemplate:
+		return n.Range
+	}
+	return parser.Range{}
+}
+
+func nodeName(node parser.TemplateFileNode) string {
+	switch n := node.(type) {
+	case *parser.HTMLTemplate:
+		return templateName(n.Expression.Value)
+	case *parser.CSSTemplate:
+		return n.Name
+	case *parser.ScriptTemplate:
+		return n.Name.Value
+	}
+	return ""
+}
+
+// templateName returns the name of the template from its declaration, e.g.
+// "Header" for "Header(title string)", or "Button.Render" for
+// "(b Button) Render(label string)".
+func templateName(expr string) string {
+	expr = strings.TrimSpace(expr)
+	var receiverType string
+	if strings.HasPrefix(expr, "(") {
+		if receiver, after, ok := strings.Cut(expr[1:], ")"); ok {
+			receiverType = receiverTypeName(receiver)
+			expr = strings.TrimSpace(after)
+		}
+	}
+	if i := strings.IndexAny(expr, "[( "); i >= 0 {
+		expr = expr[:i]
+	}
+	if receiverType != "" {
+		return receiverType + "." + expr
+	}
+	return expr
+}
+
+// receiverTypeName returns the type name from a receiver, e.g. "Box" for "b *Box[T]".
+func receiverTypeName(receiver string) string {
+	if i := strings.Index(receiver, "["); i >= 0 {
+		receiver = receiver[:i]
+	}
+	fields := strings.Fields(receiver)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(fields[len(fields)-1], "*")
+}
+
+// Report describes the result of a split.
+func Report(from, to string, moved []string) string {
+	return fmt.Sprintf("Moved %s from %s to %s", strings.Join(moved, ", "), from, to)
+}
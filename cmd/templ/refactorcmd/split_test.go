@@ -0,0 +1,281 @@
+package refactorcmd
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSplit(t *testing.T) {
+	input := `package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Props struct{}
+
+// Header renders the page header.
+templ Header(title string) {
+	<h1>{ strings.ToUpper(title) }</h1>
+}
+
+// This comment is not attached to a component.
+
+// Footer renders the page footer.
+templ Footer(year int) {
+	<footer>{ fmt.Sprint(year) }</footer>
+}
+
+css red() {
+	color: red;
+}
+`
+	expectedSource := `package main
+
+import "strings"
+
+type Props struct{}
+
+// Header renders the page header.
+templ Header(title string) {
+	<h1>{ strings.ToUpper(title) }</h1>
+}
+
+// This comment is not attached to a component.
+`
+	expectedTarget := `package main
+
+import "fmt"
+
+// Footer renders the page footer.
+templ Footer(year int) {
+	<footer>{ fmt.Sprint(year) }</footer>
+}
+
+css red() {
+	color: red;
+}
+`
+
+	dir := t.TempDir()
+	from := filepath.Join(dir, "page.templ")
+	to := filepath.Join(dir, "footer.templ")
+	if err := os.WriteFile(from, []byte(input), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	moved, err := Split(log, SplitArguments{FileName: from, To: to, Components: []string{"Footer", "red"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"Footer", "red"}, moved); diff != "" {
+		t.Errorf("unexpected moved components:\n%s", diff)
+	}
+
+	actualSource, err := os.ReadFile(from)
+	if err != nil {
+		t.Fatalf("failed to read source: %v", err)
+	}
+	if diff := cmp.Diff(expectedSource, string(actualSource)); diff != "" {
+		t.Errorf("unexpected source:\n%s", diff)
+	}
+	actualTarget, err := os.ReadFile(to)
+	if err != nil {
+		t.Fatalf("failed to read target: %v", err)
+	}
+	if diff := cmp.Diff(expectedTarget, string(actualTarget)); diff != "" {
+		t.Errorf("unexpected target:\n%s", diff)
+	}
+}
+
+func TestSplitErrors(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dir := t.TempDir()
+	from := filepath.Join(dir, "page.templ")
+	input := "package main\n\ntempl Header() {\n\t<h1></h1>\n}\n"
+	if err := os.WriteFile(from, []byte(input), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	t.Run("unknown components are an error", func(t *testing.T) {
+		_, err := Split(log, SplitArguments{FileName: from, To: filepath.Join(dir, "new.templ"), Components: []string{"Missing"}})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if _, err := os.Stat(filepath.Join(dir, "new.templ")); err == nil {
+			t.Error("expected target file not to be created")
+		}
+	})
+	t.Run("existing files are not overwritten", func(t *testing.T) {
+		_, err := Split(log, SplitArguments{FileName: from, To: from, Components: []string{"Header"}})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		actual, _ := os.ReadFile(from)
+		if string(actual) != input {
+			t.Errorf("expected source to be unchanged, got:\n%s", actual)
+		}
+	})
+}
+
+func TestSplitHeader(t *testing.T) {
+	input := `//go:build dev
+
+// Package main has development pages.
+package main
+
+templ Header() {
+	<h1></h1>
+}
+
+templ Footer() {
+	<footer></footer>
+}
+`
+	expectedTarget := `//go:build dev
+
+// Package main has development pages.
+package main
+
+templ Footer() {
+	<footer></footer>
+}
+`
+	dir := t.TempDir()
+	from := filepath.Join(dir, "page.templ")
+	to := filepath.Join(dir, "footer.templ")
+	if err := os.WriteFile(from, []byte(input), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if _, err := Split(log, SplitArguments{FileName: from, To: to, Components: []string{"Footer"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	actualTarget, err := os.ReadFile(to)
+	if err != nil {
+		t.Fatalf("failed to read target: %v", err)
+	}
+	if diff := cmp.Diff(expectedTarget, string(actualTarget)); diff != "" {
+		t.Errorf("unexpected target:\n%s", diff)
+	}
+}
+
+func TestSplitMethods(t *testing.T) {
+	input := `package main
+
+type A struct{}
+
+type B struct{}
+
+templ (a A) Render() {
+	<div>A</div>
+}
+
+templ (b *B) Render() {
+	<div>B</div>
+}
+`
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	setup := func(t *testing.T) (from, to string) {
+		dir := t.TempDir()
+		from = filepath.Join(dir, "page.templ")
+		to = filepath.Join(dir, "b.templ")
+		if err := os.WriteFile(from, []byte(input), 0o644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		return from, to
+	}
+
+	t.Run("methods can be selected by type and name", func(t *testing.T) {
+		from, to := setup(t)
+		moved, err := Split(log, SplitArguments{FileName: from, To: to, Components: []string{"B.Render"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff([]string{"B.Render"}, moved); diff != "" {
+			t.Errorf("unexpected moved components:\n%s", diff)
+		}
+		actualSource, err := os.ReadFile(from)
+		if err != nil {
+			t.Fatalf("failed to read source: %v", err)
+		}
+		if strings.Contains(string(actualSource), "(b *B) Render()") || !strings.Contains(string(actualSource), "(a A) Render()") {
+			t.Errorf("expected only B.Render to be moved, got:\n%s", actualSource)
+		}
+	})
+	t.Run("method names that match more than one type are an error", func(t *testing.T) {
+		from, to := setup(t)
+		_, err := Split(log, SplitArguments{FileName: from, To: to, Components: []string{"Render"}})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "A.Render, B.Render") {
+			t.Errorf("expected the error to list the matching components, got: %v", err)
+		}
+		if _, err := os.Stat(to); err == nil {
+			t.Error("expected target file not to be created")
+		}
+	})
+}
+
+func TestSplitPrefersExactNames(t *testing.T) {
+	input := `package main
+
+type Button struct{}
+
+templ Render() {
+	<div>Render</div>
+}
+
+templ (b Button) Render() {
+	<button></button>
+}
+`
+	dir := t.TempDir()
+	from := filepath.Join(dir, "page.templ")
+	to := filepath.Join(dir, "render.templ")
+	if err := os.WriteFile(from, []byte(input), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	moved, err := Split(log, SplitArguments{FileName: from, To: to, Components: []string{"Render"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"Render"}, moved); diff != "" {
+		t.Errorf("unexpected moved components:\n%s", diff)
+	}
+	actualSource, err := os.ReadFile(from)
+	if err != nil {
+		t.Fatalf("failed to read source: %v", err)
+	}
+	if strings.Contains(string(actualSource), "templ Render()") || !strings.Contains(string(actualSource), "(b Button) Render()") {
+		t.Errorf("expected only the top-level Render to be moved, got:\n%s", actualSource)
+	}
+}
+
+func TestTemplateName(t *testing.T) {
+	tests := map[string]string{
+		"Header()":                        "Header",
+		"Header(title string)":            "Header",
+		"(b Button) Render(label string)": "Button.Render",
+		"(b *Button) Render()":            "Button.Render",
+		"(b *Box[K, V]) Render()":         "Box.Render",
+		"List[T any](items []T)":          "List",
+	}
+	for input, expected := range tests {
+		if actual := templateName(input); actual != expected {
+			t.Errorf("%q: expected %q, got %q", input, expected, actual)
+		}
+	}
+}
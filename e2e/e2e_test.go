@@ -0,0 +1,104 @@
+// Package e2e scaffolds complete Go projects that use templ, generates code
+// for them, and checks the rendered output of the built program.
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/cmd/templ/generatecmd"
+	"golang.org/x/tools/txtar"
+)
+
+// Each archive in testdata is a project. The archive comment can set:
+//
+//	dir: the directory of the main package, relative to the project root (default ".")
+//	vendor: "true" to run go mod vendor before building
+//
+// The expected.html file contains the expected output of the program, and
+// {moduleRoot} is replaced with the path to this module in all other files.
+func TestEndToEnd(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping end-to-end tests in short mode")
+	}
+	moduleRoot, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatalf("failed to get module root: %v", err)
+	}
+	archives, err := filepath.Glob("testdata/*.txtar")
+	if err != nil {
+		t.Fatalf("failed to find test archives: %v", err)
+	}
+	for _, fileName := range archives {
+		t.Run(strings.TrimSuffix(filepath.Base(fileName), ".txtar"), func(t *testing.T) {
+			t.Parallel()
+			a, err := txtar.ParseFile(fileName)
+			if err != nil {
+				t.Fatalf("failed to parse archive: %v", err)
+			}
+			config := parseConfig(a.Comment)
+			dir := t.TempDir()
+			var expected string
+			for _, f := range a.Files {
+				if f.Name == "expected.html" {
+					expected = strings.TrimSpace(string(f.Data))
+					continue
+				}
+				data := bytes.ReplaceAll(f.Data, []byte("{moduleRoot}"), []byte(moduleRoot))
+				target := filepath.Join(dir, f.Name)
+				if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+					t.Fatalf("failed to create directory: %v", err)
+				}
+				if err := os.WriteFile(target, data, 0o644); err != nil {
+					t.Fatalf("failed to write %s: %v", f.Name, err)
+				}
+			}
+
+			err = generatecmd.Run(context.Background(), io.Discard, io.Discard, []string{"-path", dir, "-include-version=false"})
+			if err != nil {
+				t.Fatalf("failed to generate code: %v", err)
+			}
+			mainDir := filepath.Join(dir, config["dir"])
+			if config["vendor"] == "true" {
+				goCommand(t, mainDir, "mod", "vendor")
+			}
+			actual := strings.TrimSpace(goCommand(t, mainDir, "run", "."))
+			if actual != expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+			}
+		})
+	}
+}
+
+func parseConfig(comment []byte) map[string]string {
+	config := map[string]string{"dir": "."}
+	for line := range strings.Lines(string(comment)) {
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		config[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return config
+}
+
+func goCommand(t *testing.T, dir string, args ...string) (stdout string) {
+	t.Helper()
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	// Use each project's own go.mod, go.work and vendor settings.
+	cmd.Env = append(os.Environ(), "GOFLAGS=")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return string(output)
+}
@@ -78,6 +78,8 @@ type GeneratorOptions struct {
 	SkipCodeGeneratedComment bool
 	// GeneratedDate to include as a comment.
 	GeneratedDate string
+	// StrictCSP rejects inline event handlers and javascript: URLs.
+	StrictCSP bool
 }
 
 // HasGoChanged returns true if the Go code has changed between the previous and updated GeneratorOutput.
@@ -135,6 +137,11 @@ func Generate(template *parser.TemplateFile, w io.Writer, opts ...GenerateOpt) (
 			return
 		}
 	}
+	if g.options.StrictCSP {
+		if err = checkStrictCSP(template); err != nil {
+			return op, err
+		}
+	}
 	err = g.generate()
 	if err != nil {
 		return op, err
@@ -151,6 +158,8 @@ type generator struct {
 	sourceMap   *parser.SourceMap
 	variableID  int
 	childrenVar string
+	// deferredVar is the slice of components rendered at the end of the current template.
+	deferredVar string
 
 	options GeneratorOptions
 }
@@ -472,8 +481,8 @@ func (g *generator) writeTemplate(nodeIdx int, t *parser.HTMLTemplate) error {
 		return err
 	}
 	indentLevel++
-	// return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
-	if _, err = g.w.WriteIndent(indentLevel, "return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {\n"); err != nil {
+	// return templruntime.GeneratedComponent(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+	if _, err = g.w.WriteIndent(indentLevel, "return templruntime.GeneratedComponent(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {\n"); err != nil {
 		return err
 	}
 	{
@@ -526,10 +535,21 @@ func (g *generator) writeTemplate(nodeIdx int, t *parser.HTMLTemplate) error {
 		if _, err = g.w.WriteIndent(indentLevel, "ctx = templ.ClearChildren(ctx)\n"); err != nil {
 			return err
 		}
+		g.deferredVar = ""
+		if containsDeferExpression(t.Children) {
+			g.deferredVar = g.createVariableName()
+			// var templ_7745c5c3_Var2 []templ.Component
+			if _, err = g.w.WriteIndent(indentLevel, "var "+g.deferredVar+" []templ.Component\n"); err != nil {
+				return err
+			}
+		}
 		// Nodes.
 		if err = g.writeNodes(indentLevel, stripWhitespace(t.Children), nil); err != nil {
 			return err
 		}
+		if err = g.writeDeferred(indentLevel); err != nil {
+			return err
+		}
 		// return nil
 		if _, err = g.w.WriteIndent(indentLevel, "return nil\n"); err != nil {
 			return err
@@ -625,6 +645,8 @@ func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.
 		err = g.writeScriptElement(indentLevel, n)
 	case *parser.ForExpression:
 		err = g.writeForExpression(indentLevel, n, next)
+	case *parser.DeferExpression:
+		err = g.writeDeferExpression(indentLevel, n)
 	case *parser.CallTemplateExpression:
 		err = g.writeCallTemplateExpression(indentLevel, n)
 	case *parser.TemplElementExpression:
@@ -643,6 +665,10 @@ func (g *generator) writeNode(indentLevel int, current parser.Node, next parser.
 		err = g.writeText(indentLevel, n)
 	case *parser.Fallthrough:
 		err = g.writeFallthrough(indentLevel)
+	case *parser.Break:
+		err = g.writeBreak(indentLevel)
+	case *parser.Continue:
+		err = g.writeContinue(indentLevel)
 	case *parser.GoComment:
 		// Do not render Go comments in the output HTML.
 		return
@@ -742,6 +768,16 @@ func (g *generator) writeFallthrough(indentLevel int) (err error) {
 	return err
 }
 
+func (g *generator) writeBreak(indentLevel int) (err error) {
+	_, err = g.w.WriteIndent(indentLevel, "break\n")
+	return err
+}
+
+func (g *generator) writeContinue(indentLevel int) (err error) {
+	_, err = g.w.WriteIndent(indentLevel, "continue\n")
+	return err
+}
+
 func escapeQuotes(s string) string {
 	quoted := strconv.Quote(s)
 	return quoted[1 : len(quoted)-1]
@@ -1079,6 +1115,15 @@ func (g *generator) writeCallTemplateExpression(indentLevel int, n *parser.CallT
 
 func (g *generator) writeForExpression(indentLevel int, n *parser.ForExpression, next parser.Node) (err error) {
 	var r parser.Range
+	// The else branch is rendered if the loop body never runs, so track whether it did.
+	var ranName string
+	if len(n.Else) > 0 {
+		ranName = g.createVariableName()
+		// var templ_7745c5c3_Var1 bool
+		if _, err = g.w.WriteIndent(indentLevel, "var "+ranName+" bool\n"); err != nil {
+			return err
+		}
+	}
 	// for
 	if _, err = g.w.WriteIndent(indentLevel, `for `); err != nil {
 		return err
@@ -1094,6 +1139,12 @@ func (g *generator) writeForExpression(indentLevel int, n *parser.ForExpression,
 	}
 	// Children.
 	indentLevel++
+	if ranName != "" {
+		// templ_7745c5c3_Var1 = true
+		if _, err = g.w.WriteIndent(indentLevel, ranName+" = true\n"); err != nil {
+			return err
+		}
+	}
 	if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(n.Children), next); err != nil {
 		return err
 	}
@@ -1102,6 +1153,100 @@ func (g *generator) writeForExpression(indentLevel int, n *parser.ForExpression,
 	if _, err = g.w.WriteIndent(indentLevel, `}`+"\n"); err != nil {
 		return err
 	}
+	if ranName == "" {
+		return nil
+	}
+	// if !templ_7745c5c3_Var1 {
+	if _, err = g.w.WriteIndent(indentLevel, "if !"+ranName+" {\n"); err != nil {
+		return err
+	}
+	indentLevel++
+	if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(n.Else), next); err != nil {
+		return err
+	}
+	indentLevel--
+	// }
+	if _, err = g.w.WriteIndent(indentLevel, `}`+"\n"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func containsDeferExpression(nodes []parser.Node) bool {
+	for _, n := range nodes {
+		if _, ok := n.(*parser.DeferExpression); ok {
+			return true
+		}
+		if cn, ok := n.(parser.CompositeNode); ok && containsDeferExpression(cn.ChildNodes()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) writeDeferExpression(indentLevel int, n *parser.DeferExpression) (err error) {
+	// templ_7745c5c3_Var2 = append(templ_7745c5c3_Var2, templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+	if _, err = g.w.WriteIndent(indentLevel, g.deferredVar+" = append("+g.deferredVar+", templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {\n"); err != nil {
+		return err
+	}
+	indentLevel++
+	if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context\n"); err != nil {
+		return err
+	}
+	if err := g.writeTemplBuffer(indentLevel); err != nil {
+		return err
+	}
+	// ctx = templ.InitializeContext(ctx)
+	if _, err = g.w.WriteIndent(indentLevel, "ctx = templ.InitializeContext(ctx)\n"); err != nil {
+		return err
+	}
+	if err = g.writeNodes(indentLevel, stripLeadingAndTrailingWhitespace(n.Children), nil); err != nil {
+		return err
+	}
+	// return nil
+	if _, err = g.w.WriteIndent(indentLevel, "return nil\n"); err != nil {
+		return err
+	}
+	indentLevel--
+	// }))
+	if _, err = g.w.WriteIndent(indentLevel, "}))\n"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeDeferred renders the deferred components of the template, most recently deferred first,
+// in the same way as Go's defer statement.
+func (g *generator) writeDeferred(indentLevel int) (err error) {
+	if g.deferredVar == "" {
+		return nil
+	}
+	// for len(templ_7745c5c3_Var2) > 0 {
+	if _, err = g.w.WriteIndent(indentLevel, "for len("+g.deferredVar+") > 0 {\n"); err != nil {
+		return err
+	}
+	indentLevel++
+	// templ_7745c5c3_Var3 := templ_7745c5c3_Var2[len(templ_7745c5c3_Var2)-1]
+	deferredName := g.createVariableName()
+	if _, err = g.w.WriteIndent(indentLevel, deferredName+" := "+g.deferredVar+"[len("+g.deferredVar+")-1]\n"); err != nil {
+		return err
+	}
+	// templ_7745c5c3_Var2 = templ_7745c5c3_Var2[:len(templ_7745c5c3_Var2)-1]
+	if _, err = g.w.WriteIndent(indentLevel, g.deferredVar+" = "+g.deferredVar+"[:len("+g.deferredVar+")-1]\n"); err != nil {
+		return err
+	}
+	// templ_7745c5c3_Err = templ_7745c5c3_Var3.Render(ctx, templ_7745c5c3_Buffer)
+	if _, err = g.w.WriteIndent(indentLevel, "templ_7745c5c3_Err = "+deferredName+".Render(ctx, templ_7745c5c3_Buffer)\n"); err != nil {
+		return err
+	}
+	if err = g.writeErrorHandler(indentLevel); err != nil {
+		return err
+	}
+	indentLevel--
+	// }
+	if _, err = g.w.WriteIndent(indentLevel, "}\n"); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -1194,7 +1339,6 @@ func (g *generator) writeElement(indentLevel int, n *parser.Element) (err error)
 }
 
 func (g *generator) writeAttributeCSS(indentLevel int, attr *parser.ExpressionAttribute) (result *parser.ExpressionAttribute, ok bool, err error) {
-	var r parser.Range
 	name := html.EscapeString(attr.Key.String())
 	if name != "class" {
 		ok = false
@@ -1208,10 +1352,9 @@ func (g *generator) writeAttributeCSS(indentLevel int, attr *parser.ExpressionAt
 		return
 	}
 	// p.Name()
-	if r, err = g.w.Write(attr.Expression.Value); err != nil {
+	if err = g.writeCSSClassesExpression(attr.Expression); err != nil {
 		return
 	}
-	g.sourceMap.Add(attr.Expression, r)
 	// }\n
 	if _, err = g.w.Write("}\n"); err != nil {
 		return
@@ -1234,6 +1377,84 @@ func (g *generator) writeAttributeCSS(indentLevel int, attr *parser.ExpressionAt
 	return newAttr, true, nil
 }
 
+// writeCSSClassesExpression writes the items of a class attribute expression.
+// Map style entries, e.g. class={ "btn": true, "btn-primary": isPrimary }, are
+// rewritten to templ.KV calls in source order, so that the output is deterministic.
+// The value is converted to bool, so that non-bool values fail to compile instead
+// of being silently ignored at runtime. The key is left as it is, because it can be
+// a string or a templ.CSSClass, and an interface with methods can't be part of a Go
+// type constraint. Keys of other types are reported as an unknown type at runtime.
+func (g *generator) writeCSSClassesExpression(expr parser.Expression) (err error) {
+	var r parser.Range
+	const prefix = "[]any{"
+	fset := token.NewFileSet()
+	node, perr := goparser.ParseExprFrom(fset, "", prefix+expr.Value+"}", 0)
+	lit, isCompositeLit := node.(*ast.CompositeLit)
+	if perr != nil || !isCompositeLit || !hasKeyValueElements(lit) {
+		if r, err = g.w.Write(expr.Value); err != nil {
+			return err
+		}
+		g.sourceMap.Add(expr, r)
+		return nil
+	}
+	writeSubExpression := func(n ast.Node) (err error) {
+		from := fset.Position(n.Pos()).Offset - len(prefix)
+		to := fset.Position(n.End()).Offset - len(prefix)
+		line, col := positionInExpr(expr, from)
+		subExpr := parser.Expression{
+			Value: expr.Value[from:to],
+			Range: parser.Range{
+				From: parser.NewPosition(expr.Range.From.Index+int64(from), uint32(line), uint32(col)),
+			},
+		}
+		if r, err = g.w.Write(subExpr.Value); err != nil {
+			return err
+		}
+		g.sourceMap.Add(subExpr, r)
+		return nil
+	}
+	for i, elt := range lit.Elts {
+		if i > 0 {
+			if _, err = g.w.Write(", "); err != nil {
+				return err
+			}
+		}
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			if err = writeSubExpression(elt); err != nil {
+				return err
+			}
+			continue
+		}
+		// templ.KV("btn", bool(isPrimary))
+		if _, err = g.w.Write("templ.KV("); err != nil {
+			return err
+		}
+		if err = writeSubExpression(kv.Key); err != nil {
+			return err
+		}
+		if _, err = g.w.Write(", bool("); err != nil {
+			return err
+		}
+		if err = writeSubExpression(kv.Value); err != nil {
+			return err
+		}
+		if _, err = g.w.Write("))"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasKeyValueElements(lit *ast.CompositeLit) bool {
+	for _, elt := range lit.Elts {
+		if _, ok := elt.(*ast.KeyValueExpr); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (g *generator) writeAttributesCSS(indentLevel int, attrs []parser.Attribute) (err error) {
 	for i, attr := range attrs {
 		if attr, ok := attr.(*parser.ExpressionAttribute); ok {
@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+// WithStrictCSP rejects templates that would need a Content-Security-Policy
+// with 'unsafe-inline' to work: inline event handler attributes such as
+// onclick, and javascript: URLs in URL attributes such as href. Only constant
+// URLs, and templ.SafeURL conversions of string constants, can be checked.
+func WithStrictCSP() GenerateOpt {
+	return func(g *generator) error {
+		g.options.StrictCSP = true
+		return nil
+	}
+}
+
+// checkStrictCSP returns an error listing each attribute in the template file
+// that isn't allowed by a strict Content-Security-Policy.
+func checkStrictCSP(tf *parser.TemplateFile) (err error) {
+	for _, n := range tf.Nodes {
+		if t, ok := n.(*parser.HTMLTemplate); ok {
+			err = errors.Join(err, checkStrictCSPNodes(t.Children))
+		}
+	}
+	return err
+}
+
+func checkStrictCSPNodes(nodes []parser.Node) (err error) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *parser.Element:
+			err = errors.Join(err, checkStrictCSPAttributes(n.Attributes))
+		case *parser.ScriptElement:
+			err = errors.Join(err, checkStrictCSPAttributes(n.Attributes))
+		case *parser.RawElement:
+			err = errors.Join(err, checkStrictCSPAttributes(n.Attributes))
+		}
+		if c, ok := n.(parser.CompositeNode); ok {
+			err = errors.Join(err, checkStrictCSPNodes(c.ChildNodes()))
+		}
+	}
+	return err
+}
+
+func checkStrictCSPAttributes(attrs []parser.Attribute) (err error) {
+	for _, attr := range attrs {
+		var key parser.AttributeKey
+		switch attr := attr.(type) {
+		case *parser.ConditionalAttribute:
+			err = errors.Join(err, checkStrictCSPAttributes(attr.Then), checkStrictCSPAttributes(attr.Else))
+			continue
+		case *parser.ConstantAttribute:
+			key = attr.Key
+			if isURLAttribute(key) && isJavaScriptURL(attr.Value) {
+				err = errors.Join(err, strictCSPError(attr.ValueRange, fmt.Sprintf("javascript: URL in %q attribute is not allowed", key.String())))
+			}
+		case *parser.ExpressionAttribute:
+			key = attr.Key
+			if url, ok := constantSafeURL(attr.Expression.Value); ok && isURLAttribute(key) && isJavaScriptURL(url) {
+				err = errors.Join(err, strictCSPError(attr.Expression.Range, fmt.Sprintf("javascript: URL in %q attribute is not allowed", key.String())))
+			}
+		case *parser.BoolConstantAttribute:
+			key = attr.Key
+		case *parser.BoolExpressionAttribute:
+			key = attr.Key
+		}
+		// Attributes with keys that are Go expressions can't be checked until they're rendered.
+		k, ok := key.(parser.ConstantAttributeKey)
+		if !ok {
+			continue
+		}
+		if isScriptAttribute(strings.ToLower(k.Name)) {
+			err = errors.Join(err, strictCSPError(k.NameRange, fmt.Sprintf("inline event handler attribute %q is not allowed, use a script with a nonce to add event listeners", k.Name)))
+		}
+	}
+	return err
+}
+
+// urlAttributes are the attributes that contain URLs that a browser may navigate to or load.
+var urlAttributes = map[string]bool{
+	"href":       true,
+	"src":        true,
+	"action":     true,
+	"formaction": true,
+	"xlink:href": true,
+	"poster":     true,
+	"data":       true,
+	"cite":       true,
+	"background": true,
+	"ping":       true,
+	"srcset":     true,
+}
+
+func isURLAttribute(key parser.AttributeKey) bool {
+	k, ok := key.(parser.ConstantAttributeKey)
+	return ok && urlAttributes[strings.ToLower(k.Name)]
+}
+
+// constantSafeURL returns the value of a templ.SafeURL conversion of a string constant,
+// e.g. templ.SafeURL("/home"). The values of other expressions aren't known until the
+// template is rendered.
+func constantSafeURL(expr string) (url string, ok bool) {
+	e, err := goparser.ParseExpr(expr)
+	if err != nil {
+		return "", false
+	}
+	call, ok := e.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return "", false
+	}
+	fun, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || fun.Sel.Name != "SafeURL" {
+		return "", false
+	}
+	if pkg, ok := fun.X.(*ast.Ident); !ok || pkg.Name != "templ" {
+		return "", false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	url, err = strconv.Unquote(lit.Value)
+	return url, err == nil
+}
+
+// urlWhitespaceRemover removes the characters that browsers strip from URLs
+// before parsing them.
+var urlWhitespaceRemover = strings.NewReplacer("\t", "", "\n", "", "\r", "")
+
+func isJavaScriptURL(value string) bool {
+	value = urlWhitespaceRemover.Replace(html.UnescapeString(value))
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(value)), "javascript:")
+}
+
+// StrictCSPError is returned for each attribute that breaks the strict CSP rules.
+type StrictCSPError struct {
+	// Pos is the zero-based position of the attribute in the templ file.
+	Pos parser.Position
+	Msg string
+}
+
+func (e StrictCSPError) Error() string {
+	return fmt.Sprintf("strict CSP: %d:%d: %s", e.Pos.Line+1, e.Pos.Col+1, e.Msg)
+}
+
+func strictCSPError(r parser.Range, msg string) error {
+	return StrictCSPError{Pos: r.From, Msg: msg}
+}
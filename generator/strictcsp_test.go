@@ -0,0 +1,157 @@
+package generator
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ/parser/v2"
+)
+
+func TestStrictCSP(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name: "elements without inline scripts are allowed",
+			input: `package main
+
+templ Button(url templ.SafeURL) {
+	<a href={ url } class="button">Link</a>
+	<button type="button" disabled?={ true }>Click</button>
+	<a href="https://example.com/javascript:">Link</a>
+}`,
+		},
+		{
+			name: "javascript: text in attributes that aren't URLs is allowed",
+			input: `package main
+
+templ Book() {
+	<p title="javascript: the good parts">Book</p>
+	<img src="/cover.png" alt="javascript: the good parts"/>
+}`,
+		},
+		{
+			name: "inline event handlers are rejected",
+			input: `package main
+
+templ Button() {
+	<button onclick="alert('hello')">Click</button>
+}`,
+			expected: []string{`strict CSP: 4:10: inline event handler attribute "onclick" is not allowed`},
+		},
+		{
+			name: "event handlers with expression values are rejected",
+			input: `package main
+
+templ Button(handler templ.ComponentScript) {
+	<div>
+		<button onClick={ handler }>Click</button>
+	</div>
+}`,
+			expected: []string{`strict CSP: 5:11: inline event handler attribute "onClick" is not allowed`},
+		},
+		{
+			name: "htmx event handlers are rejected",
+			input: `package main
+
+templ Button() {
+	<button hx-on:click="alert('hello')">Click</button>
+}`,
+			expected: []string{`inline event handler attribute "hx-on:click" is not allowed`},
+		},
+		{
+			name: "javascript URLs are rejected",
+			input: `package main
+
+templ Link() {
+	<a href=" JavaScript:alert('hello')">Link</a>
+	<a href="java&#x09;script:alert('hello')">Link</a>
+}`,
+			expected: []string{
+				`strict CSP: 4:11: javascript: URL in "href" attribute is not allowed`,
+				`strict CSP: 5:11: javascript: URL in "href" attribute is not allowed`,
+			},
+		},
+		{
+			name: "javascript URLs in other URL attributes are rejected",
+			input: `package main
+
+templ Form() {
+	<form><button formAction="javascript:submit()">Submit</button></form>
+}`,
+			expected: []string{`javascript: URL in "formAction" attribute is not allowed`},
+		},
+		{
+			name: "constant javascript URLs converted to safe URLs are rejected",
+			input: `package main
+
+templ Link(url string) {
+	<a href={ templ.SafeURL("javascript:alert(1)") }>Link</a>
+	<a href={ templ.SafeURL("/home") }>Home</a>
+	<a href={ templ.SafeURL(url) }>Link</a>
+}`,
+			expected: []string{`strict CSP: 4:12: javascript: URL in "href" attribute is not allowed`},
+		},
+		{
+			name: "conditional attributes are checked",
+			input: `package main
+
+templ Button(enabled bool) {
+	<button
+		if enabled {
+			type="submit"
+		} else {
+			onclick="return false"
+		}
+	>Click</button>
+}`,
+			expected: []string{`inline event handler attribute "onclick" is not allowed`},
+		},
+		{
+			name: "elements within control flow are checked",
+			input: `package main
+
+templ Items(items []string) {
+	for _, item := range items {
+		if item != "" {
+			<li onmouseover="highlight()">{ item }</li>
+		}
+	}
+}`,
+			expected: []string{`inline event handler attribute "onmouseover" is not allowed`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := parser.ParseString(tt.input)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			if _, err := Generate(tf, io.Discard); err != nil {
+				t.Fatalf("without strict CSP, expected no error, got %v", err)
+			}
+			_, err = Generate(tf, io.Discard, WithStrictCSP())
+			if len(tt.expected) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			lines := strings.Split(err.Error(), "\n")
+			if len(lines) != len(tt.expected) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tt.expected), len(lines), err)
+			}
+			for i, expected := range tt.expected {
+				if !strings.Contains(lines[i], expected) {
+					t.Errorf("expected error %d to contain %q, got %q", i, expected, lines[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package testforbreakcontinue
+
+import (
+	_ "embed"
+	"os"
+	"testing"
+
+	"github.com/a-h/templ/generator/htmldiff"
+)
+
+//go:embed expected.html
+var expected string
+
+func Test(t *testing.T) {
+	component := render([]int{1, 2, 3, 4, 5, 6, 7})
+
+	actual, diff, err := htmldiff.Diff(component, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		if err := os.WriteFile("actual.html", []byte(actual), 0644); err != nil {
+			t.Errorf("failed to write actual.html: %v", err)
+		}
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,19 @@
+package testrenderstats
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func Test(t *testing.T) {
+	ctx, stats := templ.WithRenderStats(t.Context())
+	if err := page().Render(ctx, new(strings.Builder)); err != nil {
+		t.Fatal(err)
+	}
+	// page, wrap and both items, but not the children block or the deferred content.
+	if stats.Components() != 4 {
+		t.Errorf("expected 4 components, got %d", stats.Components())
+	}
+}
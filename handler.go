@@ -1,7 +1,10 @@
 package templ
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
+	"strings"
 )
 
 // ComponentHandler is a http.Handler that renders components.
@@ -12,6 +15,9 @@ type ComponentHandler struct {
 	ErrorHandler   func(r *http.Request, err error) http.Handler
 	StreamResponse bool
 	FragmentIDs    []any
+	// ETag sets an ETag header computed from the rendered content, and
+	// returns 304 Not Modified for matching If-None-Match requests.
+	ETag bool
 }
 
 const componentHandlerErrorMessage = "templ: failed to render template"
@@ -37,14 +43,7 @@ func (ch *ComponentHandler) ServeHTTPBufferedFragment(w http.ResponseWriter, r *
 		return
 	}
 
-	// The component rendered successfully, we can write the Content-Type and Status.
-	w.Header().Set("Content-Type", ch.ContentType)
-	if ch.Status != 0 {
-		w.WriteHeader(ch.Status)
-	}
-	// Ignore write error like http.Error() does, because there is
-	// no way to recover at this point.
-	_, _ = w.Write(buf.Bytes())
+	ch.writeBuffer(w, r, buf.Bytes())
 }
 
 func (ch *ComponentHandler) ServeHTTPBufferedComplete(w http.ResponseWriter, r *http.Request) {
@@ -59,14 +58,51 @@ func (ch *ComponentHandler) ServeHTTPBufferedComplete(w http.ResponseWriter, r *
 		return
 	}
 
+	ch.writeBuffer(w, r, buf.Bytes())
+}
+
+func (ch *ComponentHandler) writeBuffer(w http.ResponseWriter, r *http.Request, body []byte) {
 	// The component rendered successfully, we can write the Content-Type and Status.
 	w.Header().Set("Content-Type", ch.ContentType)
+	if ch.ETag && ch.etagApplies(r) {
+		etag := computeETag(body)
+		w.Header().Set("ETag", etag)
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
 	if ch.Status != 0 {
 		w.WriteHeader(ch.Status)
 	}
 	// Ignore write error like http.Error() does, because there is
 	// no way to recover at this point.
-	_, _ = w.Write(buf.Bytes())
+	_, _ = w.Write(body)
+}
+
+// etagApplies returns true if conditional GET rules apply to the response.
+func (ch *ComponentHandler) etagApplies(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	return ch.Status == 0 || ch.Status == http.StatusOK
+}
+
+func computeETag(body []byte) string {
+	hash := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(hash[:16]) + `"`
+}
+
+// etagMatches implements the weak comparison used for If-None-Match, see
+// https://www.rfc-editor.org/rfc/rfc9110#name-if-none-match
+func etagMatches(ifNoneMatch, etag string) bool {
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
 }
 
 func (ch *ComponentHandler) ServeHTTPBuffered(w http.ResponseWriter, r *http.Request) {
@@ -81,6 +117,28 @@ func (ch *ComponentHandler) ServeHTTPBuffered(w http.ResponseWriter, r *http.Req
 }
 
 func (ch *ComponentHandler) ServeHTTPStreamed(w http.ResponseWriter, r *http.Request) {
+	// To set an ETag before streaming, the component is rendered once to
+	// compute the hash, and only rendered again if the client needs the body.
+	if ch.ETag && ch.etagApplies(r) {
+		h := sha256.New()
+		var err error
+		if len(ch.FragmentIDs) > 0 {
+			err = RenderFragments(r.Context(), h, ch.Component, ch.FragmentIDs...)
+		} else {
+			err = ch.Component.Render(r.Context(), h)
+		}
+		if err != nil {
+			ch.handleRenderErr(w, r, err)
+			return
+		}
+		etag := `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+		w.Header().Set("ETag", etag)
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
 	// If streaming, we do not buffer the response, so set the headers immediately.
 	w.Header().Set("Content-Type", ch.ContentType)
 	if ch.Status != 0 {
@@ -161,3 +219,15 @@ func WithFragments(ids ...any) func(*ComponentHandler) {
 		ch.FragmentIDs = ids
 	}
 }
+
+// WithETag sets the ComponentHandler to send an ETag header computed from a hash
+// of the rendered content. If the request's If-None-Match header matches, a 304
+// Not Modified response is sent without a body.
+//
+// When combined with WithStreaming, the component is rendered twice: once to
+// compute the hash, and again to stream the response.
+func WithETag() func(*ComponentHandler) {
+	return func(ch *ComponentHandler) {
+		ch.ETag = true
+	}
+}
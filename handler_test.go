@@ -257,3 +257,74 @@ func TestHandler(t *testing.T) {
 		}
 	})
 }
+
+func TestHandlerETag(t *testing.T) {
+	hello := templ.Raw("Hello")
+
+	get := func(h http.Handler, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/test", nil)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	for _, streaming := range []bool{false, true} {
+		name := "buffered"
+		options := []func(*templ.ComponentHandler){templ.WithETag()}
+		if streaming {
+			name = "streamed"
+			options = append(options, templ.WithStreaming())
+		}
+		t.Run(name, func(t *testing.T) {
+			h := templ.Handler(hello, options...)
+
+			first := get(h, "")
+			if first.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, first.Code)
+			}
+			etag := first.Header().Get("ETag")
+			if etag == "" {
+				t.Fatal("expected ETag header to be set")
+			}
+			if first.Body.String() != "Hello" {
+				t.Errorf("expected body %q, got %q", "Hello", first.Body.String())
+			}
+
+			for _, ifNoneMatch := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+				w := get(h, ifNoneMatch)
+				if w.Code != http.StatusNotModified {
+					t.Errorf("If-None-Match %s: expected status %d, got %d", ifNoneMatch, http.StatusNotModified, w.Code)
+				}
+				if w.Body.Len() != 0 {
+					t.Errorf("If-None-Match %s: expected empty body, got %q", ifNoneMatch, w.Body.String())
+				}
+			}
+
+			stale := get(h, `"stale"`)
+			if stale.Code != http.StatusOK {
+				t.Errorf("expected status %d for stale ETag, got %d", http.StatusOK, stale.Code)
+			}
+			if stale.Body.String() != "Hello" {
+				t.Errorf("expected body %q for stale ETag, got %q", "Hello", stale.Body.String())
+			}
+		})
+	}
+	t.Run("ETags are not set without the option", func(t *testing.T) {
+		w := get(templ.Handler(hello), "")
+		if etag := w.Header().Get("ETag"); etag != "" {
+			t.Errorf("expected no ETag, got %q", etag)
+		}
+	})
+	t.Run("ETags are not set for non-200 responses", func(t *testing.T) {
+		w := get(templ.Handler(hello, templ.WithETag(), templ.WithStatus(http.StatusNotFound)), "*")
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+		if etag := w.Header().Get("ETag"); etag != "" {
+			t.Errorf("expected no ETag, got %q", etag)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package templ
+
+import "iter"
+
+// LoopItem is an item from a slice, along with its position in the slice.
+type LoopItem[T any] struct {
+	// Index of the item in the slice.
+	Index int
+	// Value of the item.
+	Value T
+	// First is true for the first item in the slice.
+	First bool
+	// Last is true for the last item in the slice.
+	Last bool
+}
+
+// Loop returns an iterator over the items of a slice that includes the position of each item,
+// so that separators and alternating styles don't need a manually maintained counter.
+//
+//	for item := range templ.Loop(items) {
+//		if !item.First {
+//			<hr/>
+//		}
+//		<div>{ item.Value }</div>
+//	}
+func Loop[T any](items []T) iter.Seq[LoopItem[T]] {
+	return func(yield func(LoopItem[T]) bool) {
+		for i, v := range items {
+			item := LoopItem[T]{
+				Index: i,
+				Value: v,
+				First: i == 0,
+				Last:  i == len(items)-1,
+			}
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
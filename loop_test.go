@@ -0,0 +1,61 @@
+package templ_test
+
+import (
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoop(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected []templ.LoopItem[string]
+	}{
+		{
+			name:     "a nil slice produces no items",
+			input:    nil,
+			expected: nil,
+		},
+		{
+			name:  "a single item is both first and last",
+			input: []string{"a"},
+			expected: []templ.LoopItem[string]{
+				{Index: 0, Value: "a", First: true, Last: true},
+			},
+		},
+		{
+			name:  "multiple items",
+			input: []string{"a", "b", "c"},
+			expected: []templ.LoopItem[string]{
+				{Index: 0, Value: "a", First: true},
+				{Index: 1, Value: "b"},
+				{Index: 2, Value: "c", Last: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actual []templ.LoopItem[string]
+			for item := range templ.Loop(tt.input) {
+				actual = append(actual, item)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+	t.Run("iteration can be stopped early", func(t *testing.T) {
+		var count int
+		for item := range templ.Loop([]string{"a", "b", "c"}) {
+			count++
+			if item.Index == 1 {
+				break
+			}
+		}
+		if count != 2 {
+			t.Errorf("expected 2 iterations, got %d", count)
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"github.com/a-h/parse"
+)
+
+var deferExpression parse.Parser[Node] = deferExpressionParser{}
+
+var deferStart = parse.All(parse.String("defer"), openBraceWithOptionalPadding, parse.NewLine)
+
+type deferExpressionParser struct{}
+
+func (deferExpressionParser) Parse(pi *parse.Input) (n Node, matched bool, err error) {
+	r := &DeferExpression{}
+	start := pi.Index()
+
+	// Eat "defer {\n".
+	// Text such as "defer to the owner" isn't a defer expression.
+	if _, matched, err = deferStart.Parse(pi); err != nil || !matched {
+		pi.Seek(start)
+		return r, false, err
+	}
+
+	// Node contents.
+	tnp := newTemplateNodeParser(closeBraceWithOptionalPadding, "defer expression closing brace")
+	var nodes Nodes
+	if nodes, matched, err = tnp.Parse(pi); err != nil || !matched {
+		// If we got any nodes, take them, because the LSP might want to use them.
+		r.Children = nodes.Nodes
+		return r, true, parse.Error("defer: expected nodes, but none were found", pi.Position())
+	}
+	r.Children = nodes.Nodes
+
+	// Read the required closing brace.
+	if _, matched, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !matched {
+		return r, true, parse.Error("defer: "+unterminatedMissingEnd, pi.Position())
+	}
+
+	r.Range = NewRange(pi.PositionAt(start), pi.Position())
+	return r, true, nil
+}
@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/a-h/parse"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDeferExpressionParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected any
+	}{
+		{
+			name: "defer: text",
+			input: `defer {
+	Done
+}`,
+			expected: &DeferExpression{
+				Children: []Node{
+					&Whitespace{
+						Range: Range{
+							From: Position{Index: 8, Line: 1, Col: 0},
+							To:   Position{Index: 9, Line: 1, Col: 1},
+						},
+						Value: "\t",
+					},
+					&Text{
+						Range: Range{
+							From: Position{Index: 9, Line: 1, Col: 1},
+							To:   Position{Index: 13, Line: 1, Col: 5},
+						},
+						Value:         "Done",
+						TrailingSpace: SpaceVertical,
+					},
+				},
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 15, Line: 2, Col: 1},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			actual, matched, err := deferExpression.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !matched {
+				t.Fatalf("unexpected failure for input %q", tt.input)
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDeferExpressionParserNegatives(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "defer: text",
+			input: "defer to the owner\n",
+		},
+		{
+			name:  "defer: word",
+			input: "deferred\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := parse.NewInput(tt.input)
+			_, matched, err := deferExpression.Parse(input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if matched {
+				t.Fatal("expected not to match")
+			}
+		})
+	}
+}
+
+func TestDeferExpressionParserErrors(t *testing.T) {
+	input := parse.NewInput("defer {\n\tDone\n")
+	_, matched, err := deferExpression.Parse(input)
+	if !matched {
+		t.Fatal("expected to be detected as a defer expression, but wasn't")
+	}
+	if err == nil {
+		t.Fatal("an unterminated defer expression should not be parsed successfully")
+	}
+}
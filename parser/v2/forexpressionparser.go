@@ -7,6 +7,8 @@ import (
 
 var forExpression parse.Parser[Node] = forExpressionParser{}
 
+var untilElseOrEnd = parse.Any(StripType(elseExpression), StripType(closeBraceWithOptionalPadding))
+
 type forExpressionParser struct{}
 
 func (forExpressionParser) Parse(pi *parse.Input) (n Node, matched bool, err error) {
@@ -38,7 +40,7 @@ func (forExpressionParser) Parse(pi *parse.Input) (n Node, matched bool, err err
 	}
 
 	// Node contents.
-	tnp := newTemplateNodeParser(closeBraceWithOptionalPadding, "for expression closing brace")
+	tnp := newTemplateNodeParser(untilElseOrEnd, "else expression or for expression closing brace")
 	var nodes Nodes
 	if nodes, matched, err = tnp.Parse(pi); err != nil || !matched {
 		// If we got any nodes, take them, because the LSP might want to use them.
@@ -46,6 +48,16 @@ func (forExpressionParser) Parse(pi *parse.Input) (n Node, matched bool, err err
 		return r, true, parse.Error("for: expected nodes, but none were found", pi.Position())
 	}
 	r.Children = nodes.Nodes
+	parseLoopControl(r.Children)
+
+	// Read the optional 'Else' nodes, rendered when the loop body doesn't run.
+	var elseNodes Nodes
+	if elseNodes, _, err = elseExpression.Parse(pi); err != nil {
+		// Populate the nodes anyway, so that the LSP can use them.
+		r.Else = elseNodes.Nodes
+		return r, true, err
+	}
+	r.Else = elseNodes.Nodes
 
 	// Read the required closing brace.
 	if _, matched, err = closeBraceWithOptionalPadding.Parse(pi); err != nil || !matched {
@@ -317,6 +317,60 @@ func TestForExpressionParser(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "for: with else",
+			input: `for _, item := range items {
+	{ item }
+} else {
+	None
+}`,
+			expected: &ForExpression{
+				Expression: Expression{
+					Value: `_, item := range items`,
+					Range: Range{
+						From: Position{Index: 4, Line: 0, Col: 4},
+						To:   Position{Index: 26, Line: 0, Col: 26},
+					},
+				},
+				Children: []Node{
+					&Whitespace{
+						Range: Range{
+							From: Position{Index: 29, Line: 1, Col: 0},
+							To:   Position{Index: 30, Line: 1, Col: 1},
+						},
+						Value: "\t",
+					},
+					&StringExpression{
+						Expression: Expression{
+							Value: `item`,
+							Range: Range{
+								From: Position{Index: 32, Line: 1, Col: 3},
+								To:   Position{Index: 36, Line: 1, Col: 7},
+							},
+						},
+						TrailingSpace: SpaceVertical,
+						Range: Range{
+							From: Position{Index: 30, Line: 1, Col: 1},
+							To:   Position{Index: 39, Line: 2, Col: 0},
+						},
+					},
+				},
+				Else: []Node{
+					&Text{
+						Range: Range{
+							From: Position{Index: 49, Line: 3, Col: 1},
+							To:   Position{Index: 53, Line: 3, Col: 5},
+						},
+						Value:         "None",
+						TrailingSpace: SpaceVertical,
+					},
+				},
+				Range: Range{
+					From: Position{Index: 0, Line: 0, Col: 0},
+					To:   Position{Index: 55, Line: 4, Col: 1},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"strings"
+)
+
+// parseLoopControl replaces text that is only break or continue on its own line
+// with Break and Continue nodes. It's applied to the body of a for loop, and
+// the if and switch statements within it, but not to the children of elements,
+// so that break and continue are text everywhere else.
+func parseLoopControl(nodes []Node) {
+	for i, n := range nodes {
+		switch n := n.(type) {
+		case *Text:
+			if i > 0 && !endsLine(nodes[i-1]) {
+				continue
+			}
+			if n.TrailingSpace != SpaceVertical {
+				continue
+			}
+			switch strings.TrimRight(n.Value, " \t") {
+			case "break":
+				nodes[i] = &Break{Range: n.Range}
+			case "continue":
+				nodes[i] = &Continue{Range: n.Range}
+			}
+		case *IfExpression:
+			parseLoopControl(n.Then)
+			for _, elseIf := range n.ElseIfs {
+				parseLoopControl(elseIf.Then)
+			}
+			parseLoopControl(n.Else)
+		case *SwitchExpression:
+			for _, c := range n.Cases {
+				parseLoopControl(c.Children)
+			}
+		}
+	}
+}
+
+// endsLine returns true if the node is followed by a new line.
+func endsLine(n Node) bool {
+	wt, ok := n.(WhitespaceTrailer)
+	if !ok {
+		// Statements such as if and switch end with a closing brace on its own line.
+		return true
+	}
+	return wt.Trailing() == SpaceVertical
+}
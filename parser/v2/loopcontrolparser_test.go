@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoopControlParser(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name: "break in a for loop",
+			input: `for _, item := range items {
+	break
+}`,
+			expected: []string{"break"},
+		},
+		{
+			name:     "continue with spaces before newline",
+			input:    "for _, item := range items {\n\tcontinue   \n}",
+			expected: []string{"continue"},
+		},
+		{
+			name: "continue in an if statement in a for loop",
+			input: `for _, item := range items {
+	if item.Hidden {
+		continue
+	} else if item.Last {
+		break
+	} else {
+		continue
+	}
+}`,
+			expected: []string{"continue", "break", "continue"},
+		},
+		{
+			name: "break in a switch case in a for loop",
+			input: `for _, item := range items {
+	switch item.Type {
+		case "a":
+			break
+	}
+}`,
+			expected: []string{"break"},
+		},
+		{
+			name: "text in an element in a for loop",
+			input: `for _, item := range items {
+	<a>
+		continue
+	</a>
+}`,
+			expected: []string{"text: continue"},
+		},
+		{
+			name: "text that starts with break",
+			input: `for _, item := range items {
+	break time
+}`,
+			expected: []string{"text: break time"},
+		},
+		{
+			name: "text after an element on the same line",
+			input: `for _, item := range items {
+	<b>x</b> continue
+}`,
+			expected: []string{"text: x", "text: continue"},
+		},
+		{
+			name: "text in the else branch of a for loop",
+			input: `for _, item := range items {
+	<b>x</b>
+} else {
+	break
+}`,
+			expected: []string{"text: x", "text: break"},
+		},
+		{
+			name: "text outside a for loop",
+			input: `<a>
+	continue
+</a>
+break
+if x {
+	continue
+}`,
+			expected: []string{"text: continue", "text: break", "text: continue"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf, err := ParseString("package main\n\ntempl test() {\n" + tt.input + "\n}\n")
+			if err != nil {
+				t.Fatalf("parser error: %v", err)
+			}
+			var actual []string
+			for _, n := range tf.Nodes {
+				if ht, ok := n.(*HTMLTemplate); ok {
+					actual = append(actual, loopControlNodes(ht.Children)...)
+				}
+			}
+			if diff := cmp.Diff(tt.expected, actual); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+// loopControlNodes lists the break, continue and text nodes in the tree.
+func loopControlNodes(nodes []Node) (op []string) {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case *Break:
+			op = append(op, "break")
+		case *Continue:
+			op = append(op, "continue")
+		case *Text:
+			op = append(op, "text: "+strings.TrimSpace(n.Value))
+		case *Element:
+			op = append(op, loopControlNodes(n.Children)...)
+		case *ForExpression:
+			op = append(op, loopControlNodes(n.Children)...)
+			op = append(op, loopControlNodes(n.Else)...)
+		case *IfExpression:
+			op = append(op, loopControlNodes(n.Then)...)
+			for _, elseIf := range n.ElseIfs {
+				op = append(op, loopControlNodes(elseIf.Then)...)
+			}
+			op = append(op, loopControlNodes(n.Else)...)
+		case *SwitchExpression:
+			for _, c := range n.Cases {
+				op = append(op, loopControlNodes(c.Children)...)
+			}
+		}
+	}
+	return op
+}
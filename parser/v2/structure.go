@@ -26,10 +26,13 @@ var (
 	_ Node = (*IfExpression)(nil)
 	_ Node = (*SwitchExpression)(nil)
 	_ Node = (*ForExpression)(nil)
+	_ Node = (*DeferExpression)(nil)
 	_ Node = (*StringExpression)(nil)
 	_ Node = (*GoCode)(nil)
 	_ Node = (*Whitespace)(nil)
 	_ Node = (*DocType)(nil)
+	_ Node = (*Break)(nil)
+	_ Node = (*Continue)(nil)
 )
 
 // Element nodes can have the following attributes.
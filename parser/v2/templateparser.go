@@ -73,6 +73,7 @@ var templateNodeParsers = []parse.Parser[Node]{
 	ifExpression,           // if {}
 	forExpression,          // for {}
 	switchExpression,       // switch {}
+	deferExpression,        // defer {}
 	callTemplateExpression, // {! TemplateName(a, b, c) }
 	templElementExpression, // @TemplateName(a, b, c) { <div>Children</div> }
 	childrenExpression,     // { children... }
@@ -700,6 +700,8 @@ func isBlockNode(node Node) bool {
 		return true
 	case *ForExpression:
 		return true
+	case *DeferExpression:
+		return true
 	case *Element:
 		return n.IsBlockElement() || n.IndentChildren
 	}
@@ -1213,6 +1215,35 @@ func (f *Fallthrough) Visit(v Visitor) error {
 	return v.VisitFallthrough(f)
 }
 
+// Break exits the enclosing for loop, or the enclosing switch when used in a
+// switch case, as in Go.
+type Break struct {
+	Range Range
+}
+
+func (b *Break) IsNode() bool { return true }
+func (b *Break) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, "break")
+}
+
+func (b *Break) Visit(v Visitor) error {
+	return v.VisitBreak(b)
+}
+
+// Continue skips to the next iteration of the enclosing for loop.
+type Continue struct {
+	Range Range
+}
+
+func (c *Continue) IsNode() bool { return true }
+func (c *Continue) Write(w io.Writer, indent int) error {
+	return writeIndent(w, indent, "continue")
+}
+
+func (c *Continue) Visit(v Visitor) error {
+	return v.VisitContinue(c)
+}
+
 // Nodes.
 
 // CallTemplateExpression can be used to create and render a template using data.
@@ -1444,11 +1475,16 @@ type CaseExpression struct {
 type ForExpression struct {
 	Expression Expression
 	Children   []Node
-	Range      Range
+	// Else nodes are rendered if the loop body is never executed, e.g. an empty slice.
+	Else  []Node
+	Range Range
 }
 
 func (fe ForExpression) ChildNodes() []Node {
-	return fe.Children
+	var nodes []Node
+	nodes = append(nodes, fe.Children...)
+	nodes = append(nodes, fe.Else...)
+	return nodes
 }
 func (fe *ForExpression) IsNode() bool { return true }
 func (fe *ForExpression) Write(w io.Writer, indent int) error {
@@ -1458,6 +1494,14 @@ func (fe *ForExpression) Write(w io.Writer, indent int) error {
 	if err := writeNodesIndented(w, indent+1, fe.Children); err != nil {
 		return err
 	}
+	if len(fe.Else) > 0 {
+		if err := writeIndent(w, indent, "} else {\n"); err != nil {
+			return err
+		}
+		if err := writeNodesIndented(w, indent+1, fe.Else); err != nil {
+			return err
+		}
+	}
 	if err := writeIndent(w, indent, "}"); err != nil {
 		return err
 	}
@@ -1468,6 +1512,38 @@ func (fe *ForExpression) Visit(v Visitor) error {
 	return v.VisitForExpression(fe)
 }
 
+// DeferExpression contains nodes that are rendered at the end of the template,
+// after all other content.
+//
+//	defer {
+//		<script src="modal.js"></script>
+//	}
+type DeferExpression struct {
+	Children []Node
+	Range    Range
+}
+
+func (de DeferExpression) ChildNodes() []Node {
+	return de.Children
+}
+func (de *DeferExpression) IsNode() bool { return true }
+func (de *DeferExpression) Write(w io.Writer, indent int) error {
+	if err := writeIndent(w, indent, "defer {\n"); err != nil {
+		return err
+	}
+	if err := writeNodesIndented(w, indent+1, de.Children); err != nil {
+		return err
+	}
+	if err := writeIndent(w, indent, "}"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (de *DeferExpression) Visit(v Visitor) error {
+	return v.VisitDeferExpression(de)
+}
+
 // GoCode is used within HTML elements, and allows arbitrary go code.
 // {{ ... }}
 type GoCode struct {
@@ -29,8 +29,11 @@ type Visitor interface {
 	VisitIfExpression(*IfExpression) error
 	VisitSwitchExpression(*SwitchExpression) error
 	VisitForExpression(*ForExpression) error
+	VisitDeferExpression(*DeferExpression) error
 	VisitGoCode(*GoCode) error
 	VisitStringExpression(*StringExpression) error
 	VisitScriptTemplate(*ScriptTemplate) error
 	VisitFallthrough(*Fallthrough) error
+	VisitBreak(*Break) error
+	VisitContinue(*Continue) error
 }
@@ -172,6 +172,19 @@ func New() *Visitor {
 		return nil
 	}
 	v.ForExpression = func(n *parser.ForExpression) error {
+		for _, child := range n.Children {
+			if err := child.Visit(v); err != nil {
+				return err
+			}
+		}
+		for _, child := range n.Else {
+			if err := child.Visit(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	v.DeferExpression = func(n *parser.DeferExpression) error {
 		for _, child := range n.Children {
 			if err := child.Visit(v); err != nil {
 				return err
@@ -191,6 +204,12 @@ func New() *Visitor {
 	v.Fallthrough = func(n *parser.Fallthrough) error {
 		return nil
 	}
+	v.Break = func(n *parser.Break) error {
+		return nil
+	}
+	v.Continue = func(n *parser.Continue) error {
+		return nil
+	}
 
 	return v
 }
@@ -225,10 +244,13 @@ type Visitor struct {
 	IfExpression             func(n *parser.IfExpression) error
 	SwitchExpression         func(n *parser.SwitchExpression) error
 	ForExpression            func(n *parser.ForExpression) error
+	DeferExpression          func(n *parser.DeferExpression) error
 	GoCode                   func(n *parser.GoCode) error
 	StringExpression         func(n *parser.StringExpression) error
 	ScriptTemplate           func(n *parser.ScriptTemplate) error
 	Fallthrough              func(n *parser.Fallthrough) error
+	Break                    func(n *parser.Break) error
+	Continue                 func(n *parser.Continue) error
 }
 
 var _ parser.Visitor = (*Visitor)(nil)
@@ -340,6 +362,10 @@ func (v *Visitor) VisitForExpression(n *parser.ForExpression) error {
 	return v.ForExpression(n)
 }
 
+func (v *Visitor) VisitDeferExpression(n *parser.DeferExpression) error {
+	return v.DeferExpression(n)
+}
+
 func (v *Visitor) VisitGoCode(n *parser.GoCode) error {
 	return v.GoCode(n)
 }
@@ -355,3 +381,11 @@ func (v *Visitor) VisitScriptTemplate(n *parser.ScriptTemplate) error {
 func (v *Visitor) VisitFallthrough(n *parser.Fallthrough) error {
 	return v.Fallthrough(n)
 }
+
+func (v *Visitor) VisitBreak(n *parser.Break) error {
+	return v.Break(n)
+}
+
+func (v *Visitor) VisitContinue(n *parser.Continue) error {
+	return v.Continue(n)
+}
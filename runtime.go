@@ -223,6 +223,18 @@ func KV[TKey comparable, TValue any](key TKey, value TValue) KeyValue[TKey, TVal
 	}
 }
 
+// If returns then if the condition is true, otherwise it returns otherwise.
+// It's useful for choosing between attribute values without an if block, e.g.
+// title={ templ.If(isAdmin, "Administrator", "User") }.
+//
+// Both arguments are evaluated, and must be of the same type.
+func If[T any](condition bool, then, otherwise T) T {
+	if condition {
+		return then
+	}
+	return otherwise
+}
+
 const unknownTypeClassName = "--templ-css-class-unknown-type"
 
 // Class returns a CSS class name.
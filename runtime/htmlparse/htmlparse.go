@@ -0,0 +1,128 @@
+// Package htmlparse renders templ components into a tree of HTML nodes, and
+// provides helpers to query the tree, for post-processing and for tests.
+package htmlparse
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/a-h/templ"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Render renders the component and parses the output.
+//
+// If the output is a complete HTML document, the document node is returned.
+// Otherwise, the output is parsed as a fragment within a <body> element, and
+// the nodes are returned as children of an empty document node, so that the
+// result can always be queried from the root.
+func Render(ctx context.Context, c templ.Component) (root *html.Node, err error) {
+	var buf bytes.Buffer
+	if err = c.Render(ctx, &buf); err != nil {
+		return nil, err
+	}
+	if isDocument(buf.Bytes()) {
+		return html.Parse(&buf)
+	}
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(&buf, body)
+	if err != nil {
+		return nil, err
+	}
+	root = &html.Node{Type: html.DocumentNode}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+	return root, nil
+}
+
+func isDocument(b []byte) bool {
+	b = bytes.TrimLeftFunc(b, unicode.IsSpace)
+	prefix := bytes.ToLower(b[:min(len(b), len("<!doctype"))])
+	return bytes.HasPrefix(prefix, []byte("<!doctype")) || bytes.HasPrefix(prefix, []byte("<html"))
+}
+
+// All returns all nodes that match the given function.
+func All(n *html.Node, f Matcher) (nodes []*html.Node) {
+	if f(n) {
+		nodes = append(nodes, n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		nodes = append(nodes, All(c, f)...)
+	}
+	return nodes
+}
+
+// First returns the first node that matches the given function, in document order.
+func First(n *html.Node, f Matcher) (node *html.Node, ok bool) {
+	if f(n) {
+		return n, true
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if node, ok = First(c, f); ok {
+			return node, true
+		}
+	}
+	return nil, false
+}
+
+// Matcher is a function that matches HTML nodes.
+type Matcher func(*html.Node) bool
+
+// Attribute is a key-value pair for an HTML element.
+type Attribute struct {
+	Name, Value string
+}
+
+// Attr is a constructor for Attribute.
+func Attr(name, value string) Attribute {
+	return Attribute{name, value}
+}
+
+// Element returns a Matcher that matches an HTML element with the given name and attributes.
+func Element(name string, attrs ...Attribute) Matcher {
+	return func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return false
+		}
+		if n.Data != name {
+			return false
+		}
+		for _, a := range attrs {
+			if v, _ := AttrValue(n, a.Name); v != a.Value {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AttrValue returns the value of the named attribute of the node.
+func AttrValue(n *html.Node, name string) (value string, ok bool) {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// Text returns the text content of the node and its descendants.
+func Text(n *html.Node) string {
+	var sb strings.Builder
+	writeText(&sb, n)
+	return sb.String()
+}
+
+func writeText(sb *strings.Builder, n *html.Node) {
+	if n.Type == html.TextNode {
+		sb.WriteString(n.Data)
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeText(sb, c)
+	}
+}
@@ -0,0 +1,110 @@
+package htmlparse_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/runtime/htmlparse"
+	"golang.org/x/net/html"
+)
+
+func raw(s string) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, s)
+		return err
+	})
+}
+
+func TestRender(t *testing.T) {
+	t.Run("fragments are children of the root", func(t *testing.T) {
+		root, err := htmlparse.Render(context.Background(), raw(`<li>a</li><li>b</li>`))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if root.Type != html.DocumentNode {
+			t.Errorf("expected a document node, got %v", root.Type)
+		}
+		items := htmlparse.All(root, htmlparse.Element("li"))
+		if len(items) != 2 {
+			t.Fatalf("expected 2 items, got %d", len(items))
+		}
+		if items[0].Parent != root {
+			t.Error("expected fragment nodes to be children of the root")
+		}
+		if htmlparse.Element("body")(root.FirstChild) {
+			t.Error("expected fragment not to be wrapped in a body element")
+		}
+	})
+	t.Run("documents are parsed with their head and body", func(t *testing.T) {
+		root, err := htmlparse.Render(context.Background(), raw("\n<!DOCTYPE html><html><head><title>Title</title></head><body><h1>Heading</h1></body></html>"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		title, ok := htmlparse.First(root, htmlparse.Element("title"))
+		if !ok {
+			t.Fatal("expected a title element")
+		}
+		if title.Parent.Data != "head" {
+			t.Errorf("expected title to be in the head, got %q", title.Parent.Data)
+		}
+		if _, ok := htmlparse.First(root, htmlparse.Element("body")); !ok {
+			t.Error("expected a body element")
+		}
+	})
+	t.Run("render errors are returned", func(t *testing.T) {
+		expected := errors.New("render failed")
+		c := templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			return expected
+		})
+		if _, err := htmlparse.Render(context.Background(), c); !errors.Is(err, expected) {
+			t.Errorf("expected %v, got %v", expected, err)
+		}
+	})
+}
+
+func TestQuery(t *testing.T) {
+	root, err := htmlparse.Render(context.Background(), raw(`<nav>
+	<a href="/a" class="active">A <b>bold</b></a>
+	<a href="/b">B</a>
+</nav>`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Run("All returns matching nodes in document order", func(t *testing.T) {
+		links := htmlparse.All(root, htmlparse.Element("a"))
+		if len(links) != 2 {
+			t.Fatalf("expected 2 links, got %d", len(links))
+		}
+		if href, _ := htmlparse.AttrValue(links[1], "href"); href != "/b" {
+			t.Errorf("expected second link to be /b, got %q", href)
+		}
+	})
+	t.Run("Element matches attributes", func(t *testing.T) {
+		active, ok := htmlparse.First(root, htmlparse.Element("a", htmlparse.Attr("class", "active")))
+		if !ok {
+			t.Fatal("expected an active link")
+		}
+		if href, _ := htmlparse.AttrValue(active, "href"); href != "/a" {
+			t.Errorf("expected active link to be /a, got %q", href)
+		}
+	})
+	t.Run("First returns false if nothing matches", func(t *testing.T) {
+		if _, ok := htmlparse.First(root, htmlparse.Element("table")); ok {
+			t.Error("expected no match")
+		}
+	})
+	t.Run("AttrValue returns false for missing attributes", func(t *testing.T) {
+		if _, ok := htmlparse.AttrValue(root.FirstChild, "id"); ok {
+			t.Error("expected missing attribute")
+		}
+	})
+	t.Run("Text returns the text of descendants", func(t *testing.T) {
+		a, _ := htmlparse.First(root, htmlparse.Element("a"))
+		if text := htmlparse.Text(a); text != "A bold" {
+			t.Errorf("expected %q, got %q", "A bold", text)
+		}
+	})
+}
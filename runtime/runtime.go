@@ -19,3 +19,13 @@ func GeneratedTemplate(f func(GeneratedComponentInput) error) templ.Component {
 		return f(GeneratedComponentInput{ctx, w})
 	})
 }
+
+// GeneratedComponent is used by the generated constructor of each templ component.
+// Unlike GeneratedTemplate, which is also used for children blocks and deferred content,
+// rendering it counts a component in the templ.RenderStats of the context.
+func GeneratedComponent(f func(GeneratedComponentInput) error) templ.Component {
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		templ.GetRenderStats(ctx).AddComponent()
+		return f(GeneratedComponentInput{ctx, w})
+	})
+}
@@ -2,8 +2,11 @@ package runtime
 
 import (
 	"context"
+	"io"
 	"strings"
 	"testing"
+
+	"github.com/a-h/templ"
 )
 
 func TestGeneratedTemplate(t *testing.T) {
@@ -20,3 +23,25 @@ func TestGeneratedTemplate(t *testing.T) {
 		t.Errorf("expected \"Hello, World!\", got %q", sb.String())
 	}
 }
+
+func BenchmarkGeneratedComponent(b *testing.B) {
+	c := GeneratedComponent(func(input GeneratedComponentInput) error {
+		return nil
+	})
+	// Run before render stats are enabled, to measure the cost for programs that don't use them.
+	b.Run("without render stats", func(b *testing.B) {
+		ctx := context.WithValue(context.Background(), struct{}{}, "value")
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = c.Render(ctx, io.Discard)
+		}
+	})
+	b.Run("with render stats", func(b *testing.B) {
+		ctx, _ := templ.WithRenderStats(context.Background())
+		ctx = context.WithValue(ctx, struct{}{}, "value")
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = c.Render(ctx, io.Discard)
+		}
+	})
+}
@@ -884,6 +884,18 @@ func TestHoistFallback(t *testing.T) {
 	})
 }
 
+func TestIf(t *testing.T) {
+	if got := templ.If(true, "a", "b"); got != "a" {
+		t.Errorf("expected %q, got %q", "a", got)
+	}
+	if got := templ.If(false, "a", "b"); got != "b" {
+		t.Errorf("expected %q, got %q", "b", got)
+	}
+	if got := templ.If(false, templ.SafeURL("/a"), "/b"); got != templ.SafeURL("/b") {
+		t.Errorf("expected %q, got %q", "/b", got)
+	}
+}
+
 func ptr[T any](x T) *T {
 	return &x
 }
@@ -0,0 +1,101 @@
+package templ
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// RenderStats records statistics about the components rendered while serving
+// a request.
+type RenderStats struct {
+	components atomic.Int64
+	bytes      atomic.Int64
+	start      time.Time
+	duration   time.Duration
+}
+
+// Components returns the number of templ components rendered.
+func (s *RenderStats) Components() int64 { return s.components.Load() }
+
+// Bytes returns the number of bytes written to the response.
+func (s *RenderStats) Bytes() int64 { return s.bytes.Load() }
+
+// Duration returns the time taken to handle the request.
+func (s *RenderStats) Duration() time.Duration { return s.duration }
+
+// AddComponent increments the count of rendered components. It's called each
+// time a templ component is rendered, but not for children blocks or deferred
+// content, and does nothing if s is nil.
+func (s *RenderStats) AddComponent() {
+	if s == nil {
+		return
+	}
+	s.components.Add(1)
+}
+
+type renderStatsKeyType int
+
+const renderStatsKey = renderStatsKeyType(0)
+
+// renderStatsEnabled is set once WithRenderStats has been used, so that
+// rendering doesn't look up the context value in programs that don't collect
+// render statistics.
+var renderStatsEnabled atomic.Bool
+
+// WithRenderStats returns a context that collects render statistics.
+func WithRenderStats(ctx context.Context) (context.Context, *RenderStats) {
+	renderStatsEnabled.Store(true)
+	s := &RenderStats{start: time.Now()}
+	return context.WithValue(ctx, renderStatsKey, s), s
+}
+
+// GetRenderStats returns the render statistics collected in the context, or
+// nil if WithRenderStats has not been used.
+func GetRenderStats(ctx context.Context) *RenderStats {
+	if ctx == nil || !renderStatsEnabled.Load() {
+		return nil
+	}
+	s, _ := ctx.Value(renderStatsKey).(*RenderStats)
+	return s
+}
+
+// RenderStatsMiddleware collects render statistics for each request handled
+// by next, and passes them to report once the request has been handled.
+//
+// For example, to log the statistics:
+//
+//	templ.RenderStatsMiddleware(handler, func(r *http.Request, s *templ.RenderStats) {
+//		slog.Info("rendered", slog.String("path", r.URL.Path), slog.Int64("components", s.Components()), slog.Int64("bytes", s.Bytes()), slog.Duration("duration", s.Duration()))
+//	})
+func RenderStatsMiddleware(next http.Handler, report func(r *http.Request, s *RenderStats)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, s := WithRenderStats(r.Context())
+		r = r.WithContext(ctx)
+		next.ServeHTTP(&statsResponseWriter{ResponseWriter: w, stats: s}, r)
+		s.duration = time.Since(s.start)
+		report(r, s)
+	})
+}
+
+type statsResponseWriter struct {
+	http.ResponseWriter
+	stats *RenderStats
+}
+
+func (w *statsResponseWriter) Write(p []byte) (n int, err error) {
+	n, err = w.ResponseWriter.Write(p)
+	w.stats.bytes.Add(int64(n))
+	return n, err
+}
+
+// Flush allows streamed responses to be flushed through the middleware.
+func (w *statsResponseWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap allows http.ResponseController to access the underlying writer.
+func (w *statsResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
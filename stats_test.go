@@ -0,0 +1,56 @@
+package templ_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/templ"
+	templruntime "github.com/a-h/templ/runtime"
+)
+
+func TestRenderStatsMiddleware(t *testing.T) {
+	item := templruntime.GeneratedComponent(func(input templruntime.GeneratedComponentInput) error {
+		_, err := io.WriteString(input.Writer, "<li></li>")
+		return err
+	})
+	list := templruntime.GeneratedComponent(func(input templruntime.GeneratedComponentInput) error {
+		for range 3 {
+			if err := item.Render(input.Context, input.Writer); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	var components, bytes int64
+	var reported bool
+	h := templ.RenderStatsMiddleware(templ.Handler(list, templ.WithStreaming()), func(r *http.Request, s *templ.RenderStats) {
+		reported = true
+		components, bytes = s.Components(), s.Bytes()
+	})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !reported {
+		t.Fatal("expected stats to be reported")
+	}
+	if components != 4 {
+		t.Errorf("expected 4 components, got %d", components)
+	}
+	if expected := int64(len("<li></li>") * 3); bytes != expected {
+		t.Errorf("expected %d bytes, got %d", expected, bytes)
+	}
+	if w.Body.String() != "<li></li><li></li><li></li>" {
+		t.Errorf("unexpected body: %q", w.Body.String())
+	}
+}
+
+func TestRenderStatsWithoutMiddleware(t *testing.T) {
+	if s := templ.GetRenderStats(t.Context()); s != nil {
+		t.Errorf("expected nil stats, got %v", s)
+	}
+	// Adding components to nil stats is a no-op.
+	templ.GetRenderStats(t.Context()).AddComponent()
+}
@@ -0,0 +1,28 @@
+// Package templtest provides helpers for testing templ components.
+package templtest
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+// WithFrozenTime returns a context where templ.Now always returns t.
+func WithFrozenTime(ctx context.Context, t time.Time) context.Context {
+	return templ.WithClock(ctx, func() time.Time { return t })
+}
+
+// WithSeed returns a context where templ.Rand returns a generator seeded with
+// seed, so that random values, such as element IDs, are the same each time the
+// components are rendered.
+func WithSeed(ctx context.Context, seed uint64) context.Context {
+	return templ.WithRandSource(ctx, rand.NewPCG(seed, seed))
+}
+
+// Deterministic returns a context with a frozen clock and a seeded random
+// source, suitable for snapshot testing.
+func Deterministic(ctx context.Context, t time.Time, seed uint64) context.Context {
+	return WithSeed(WithFrozenTime(ctx, t), seed)
+}
@@ -0,0 +1,43 @@
+package templtest_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/a-h/templ/templtest"
+)
+
+var timestamped = templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+	_, err := fmt.Fprintf(w, `<p id="p-%d">%s</p>`, templ.Rand(ctx).IntN(1000000), templ.Now(ctx).Format(time.DateOnly))
+	return err
+})
+
+func render(ctx context.Context, t *testing.T) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := timestamped.Render(ctx, &buf); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	return buf.String()
+}
+
+func TestDeterministic(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	first := render(templtest.Deterministic(context.Background(), now, 42), t)
+	second := render(templtest.Deterministic(context.Background(), now, 42), t)
+	if first != second {
+		t.Errorf("expected renders to match, got %q and %q", first, second)
+	}
+
+	frozen := render(templtest.WithFrozenTime(context.Background(), now), t)
+	if !strings.Contains(frozen, "2024-03-01") {
+		t.Errorf("expected frozen date in output, got %q", frozen)
+	}
+}